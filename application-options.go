@@ -0,0 +1,166 @@
+package qamel
+
+import (
+	"fmt"
+	"sort"
+)
+
+// SceneGraphBackend is the backend that used by Qt Quick to render the scene graph.
+type SceneGraphBackend int32
+
+const (
+	// BackendDefault lets Qt choose the backend, which is OpenGL unless
+	// it's overridden by QT_QUICK_BACKEND environment variable.
+	BackendDefault SceneGraphBackend = 0
+	// BackendSoftware renders the scene graph using the raster paint engine,
+	// so it works on system without GPU. However, it doesn't support shader
+	// effects and particles.
+	BackendSoftware = 1
+	// BackendOpenGL renders the scene graph using OpenGL. The application can't be
+	// constructed if OpenGL is not available, since it's never replaced by fallback.
+	BackendOpenGL = 2
+)
+
+// HighDpiPolicy describes how the application handles high-DPI screens.
+type HighDpiPolicy int32
+
+const (
+	// HighDpiScaling enables high-DPI scaling, which makes Qt scale the coordinate
+	// system according to the display scale factors. This is the default policy.
+	HighDpiScaling HighDpiPolicy = 0
+	// HighDpiNoScaling disables high-DPI scaling, exposing window system coordinates.
+	HighDpiNoScaling = 1
+	// HighDpiSystem leaves the high-DPI scaling to the default behavior of Qt,
+	// which can be configured by QT_AUTO_SCREEN_SCALE_FACTOR environment variable.
+	HighDpiSystem = 2
+)
+
+// ScaleFactorRounding is the policy for rounding the fractional scale factor,
+// e.g. 1.5 on 144 DPI screen. This requires Qt 5.14 or newer, it's ignored otherwise.
+type ScaleFactorRounding int32
+
+const (
+	// RoundingDefault uses the default rounding policy of Qt.
+	RoundingDefault ScaleFactorRounding = 0
+	// RoundingRound rounds up for .5 and above.
+	RoundingRound = 1
+	// RoundingCeil always rounds up.
+	RoundingCeil = 2
+	// RoundingFloor always rounds down.
+	RoundingFloor = 3
+	// RoundingRoundPreferFloor rounds up for .75 and above.
+	RoundingRoundPreferFloor = 4
+	// RoundingPassThrough doesn't round, so the fractional scale factor is used as it is.
+	RoundingPassThrough = 5
+)
+
+// SurfaceFormat is the default format of the surface that used by the windows.
+type SurfaceFormat struct {
+	// Samples is the number of samples per pixel for multisample anti-aliasing.
+	// It must be 0 (disabled) or power of two, e.g. 2, 4, 8 or 16.
+	Samples int
+
+	// Alpha enables the alpha buffer, which is required for translucent window.
+	Alpha bool
+}
+
+// Options is the options for constructing the application.
+type Options struct {
+	// Backend is the scene graph backend for rendering QML.
+	Backend SceneGraphBackend
+
+	// NoSoftwareFallback disables the automatic fallback to software backend.
+	// By default, if OpenGL is not available (e.g. in VM without GPU), the
+	// software backend will be used so the application still can be run.
+	// The fallback only applies to BackendDefault without QT_QUICK_BACKEND set.
+	NoSoftwareFallback bool
+
+	// HighDpi is the policy for high-DPI screens.
+	HighDpi HighDpiPolicy
+
+	// ScaleFactorRounding is the rounding policy for fractional scale factor.
+	ScaleFactorRounding ScaleFactorRounding
+
+	// SurfaceFormat is the default surface format for all windows.
+	SurfaceFormat SurfaceFormat
+
+	// FontDir is the directory whose fonts are registered once the application
+	// constructed, e.g. "qrc:/res/fonts". See AddApplicationFontDir.
+	FontDir string
+
+	// Attributes is the application attributes that will be set (if true) or
+	// cleared (if false) before the application constructed. The high-DPI
+	// attributes must not conflict with HighDpi policy.
+	Attributes map[Attribute]bool
+}
+
+// validate checks if the options are valid and don't conflict with each other.
+func (opts Options) validate() error {
+	switch opts.Backend {
+	case BackendDefault, BackendSoftware, BackendOpenGL:
+	default:
+		return fmt.Errorf("unknown scene graph backend %d", opts.Backend)
+	}
+
+	switch opts.HighDpi {
+	case HighDpiScaling, HighDpiNoScaling, HighDpiSystem:
+	default:
+		return fmt.Errorf("unknown high-DPI policy %d", opts.HighDpi)
+	}
+
+	if opts.ScaleFactorRounding < RoundingDefault || opts.ScaleFactorRounding > RoundingPassThrough {
+		return fmt.Errorf("unknown scale factor rounding %d", opts.ScaleFactorRounding)
+	}
+
+	samples := opts.SurfaceFormat.Samples
+	if samples < 0 || samples&(samples-1) != 0 {
+		return fmt.Errorf("samples must be 0 or power of two, got %d", samples)
+	}
+
+	if opts.Attributes[EnableHighDpiScaling] && opts.Attributes[DisableHighDpiScaling] {
+		return fmt.Errorf("high-DPI scaling can't be enabled and disabled at the same time")
+	}
+
+	_, hasEnable := opts.Attributes[EnableHighDpiScaling]
+	_, hasDisable := opts.Attributes[DisableHighDpiScaling]
+	if (hasEnable || hasDisable) && opts.HighDpi != HighDpiSystem {
+		return fmt.Errorf("high-DPI attributes can only be used with HighDpiSystem policy")
+	}
+
+	nOpenGL := 0
+	for _, attr := range []Attribute{UseDesktopOpenGL, UseOpenGLES, UseSoftwareOpenGL} {
+		if opts.Attributes[attr] {
+			nOpenGL++
+		}
+	}
+
+	if nOpenGL > 1 {
+		return fmt.Errorf("only one of UseDesktopOpenGL, UseOpenGLES and UseSoftwareOpenGL can be set")
+	}
+
+	return nil
+}
+
+// apply sets the application attributes. It must be called before the application constructed.
+func (opts Options) apply() {
+	switch opts.HighDpi {
+	case HighDpiScaling:
+		SetAttribute(EnableHighDpiScaling, true)
+	case HighDpiNoScaling:
+		SetAttribute(DisableHighDpiScaling, true)
+	}
+
+	// Sort the attributes so they are applied in consistent order
+	attributes := make([]Attribute, 0, len(opts.Attributes))
+	for attr := range opts.Attributes {
+		attributes = append(attributes, attr)
+	}
+
+	sort.Slice(attributes, func(i, j int) bool {
+		return attributes[i] < attributes[j]
+	})
+
+	for _, attr := range attributes {
+		SetAttribute(attr, opts.Attributes[attr])
+	}
+}
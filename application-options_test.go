@@ -0,0 +1,55 @@
+package qamel
+
+import "testing"
+
+func TestOptionsValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    Options
+		wantErr bool
+	}{
+		{name: "default", opts: Options{}},
+		{name: "software backend", opts: Options{Backend: BackendSoftware}},
+		{name: "unknown backend", opts: Options{Backend: SceneGraphBackend(99)}, wantErr: true},
+		{name: "unknown high-DPI policy", opts: Options{HighDpi: HighDpiPolicy(99)}, wantErr: true},
+		{name: "unknown rounding", opts: Options{ScaleFactorRounding: RoundingPassThrough + 1}, wantErr: true},
+		{name: "power of two samples", opts: Options{SurfaceFormat: SurfaceFormat{Samples: 4}}},
+		{name: "odd samples", opts: Options{SurfaceFormat: SurfaceFormat{Samples: 3}}, wantErr: true},
+		{name: "negative samples", opts: Options{SurfaceFormat: SurfaceFormat{Samples: -2}}, wantErr: true},
+		{
+			name: "high-DPI attribute with system policy",
+			opts: Options{
+				HighDpi:    HighDpiSystem,
+				Attributes: map[Attribute]bool{EnableHighDpiScaling: true},
+			},
+		}, {
+			name: "high-DPI attribute without system policy",
+			opts: Options{
+				Attributes: map[Attribute]bool{DisableHighDpiScaling: true},
+			},
+			wantErr: true,
+		}, {
+			name: "conflicting high-DPI attributes",
+			opts: Options{
+				HighDpi:    HighDpiSystem,
+				Attributes: map[Attribute]bool{EnableHighDpiScaling: true, DisableHighDpiScaling: true},
+			},
+			wantErr: true,
+		}, {
+			name: "several OpenGL implementations",
+			opts: Options{
+				Attributes: map[Attribute]bool{UseDesktopOpenGL: true, UseOpenGLES: true},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.opts.validate()
+			if (err != nil) != test.wantErr {
+				t.Errorf("expected error %v, got %v", test.wantErr, err)
+			}
+		})
+	}
+}
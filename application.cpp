@@ -1,22 +1,88 @@
+#include "_cgo_export.h"
 #include "application.h"
+#include "utils.h"
 #include <QApplication>
 #include <QFont>
 #include <QString>
 #include <QIcon>
 #include <QByteArray>
 #include <QList>
+#include <QMetaObject>
+#include <QCoreApplication>
+#include <string.h>
+#include <QOpenGLContext>
+#include <QSurfaceFormat>
+#include <QQuickWindow>
+#include <QSGRendererInterface>
+#include <QtGlobal>
 
-void* App_NewApplication(int argc, char* argv) {
-	static int argcs = argc;
-	static char** argvs = static_cast<char**>(malloc(argcs * sizeof(char*)));
+// qamelOpenGLAvailable checks if OpenGL context could be created in this system.
+static bool qamelOpenGLAvailable() {
+    QOpenGLContext context;
+    return context.create();
+}
+
+void* App_NewApplication(int argc, char** argv, QamelAppOptions options, char** errorMessage) {
+    // Rounding policy and surface format must be set before the application constructed
+#if QT_VERSION >= QT_VERSION_CHECK(5, 14, 0)
+    if (options.scaleFactorRounding > 0) {
+        QApplication::setHighDpiScaleFactorRoundingPolicy(
+            Qt::HighDpiScaleFactorRoundingPolicy(options.scaleFactorRounding));
+    }
+#endif
+
+    if (options.samples > 0 || options.alpha) {
+        QSurfaceFormat format = QSurfaceFormat::defaultFormat();
+        if (options.samples > 0) {
+            format.setSamples(options.samples);
+        }
+        if (options.alpha) {
+            format.setAlphaBufferSize(8);
+        }
+        QSurfaceFormat::setDefaultFormat(format);
+    }
+
+    // QApplication keeps the reference to argc and argv, so both
+    // must stay valid as long as the application is alive.
+    int *appArgc = new int(argc);
+    char** appArgv = static_cast<char**>(malloc((argc + 1) * sizeof(char*)));
+    for (int i = 0; i < argc; i++) {
+        appArgv[i] = strdup(argv[i]);
+    }
+    appArgv[argc] = nullptr;
+
+    QApplication *app = new QApplication(*appArgc, appArgv);
+    QObject::connect(app, &QCoreApplication::aboutToQuit, []() {
+        qamelAboutToQuit();
+    });
 
-	QList<QByteArray> aList = QByteArray(argv).split('|');
-	for (int i = 0; i < argcs; i++) {
-		argvs[i] = (new QByteArray(aList.at(i)))->data();
-	}
+    // Scene graph backend must be set before the first window created. The OpenGL probe
+    // is only done when the backend is left to Qt, so explicit choice is never overridden.
+    *errorMessage = nullptr;
+    switch (options.backend) {
+    case 1:
+        QQuickWindow::setSceneGraphBackend(QSGRendererInterface::Software);
+        break;
+    case 2:
+        if (!qamelOpenGLAvailable()) {
+            *errorMessage = qamelCString("OpenGL backend is requested but OpenGL is not available");
+            delete app;
+            return nullptr;
+        }
 
-    QApplication::setAttribute(Qt::AA_EnableHighDpiScaling);
-    return new QApplication(argcs, argvs);
+        QQuickWindow::setSceneGraphBackend(QSGRendererInterface::OpenGL);
+        break;
+    default:
+        bool envBackend = !qEnvironmentVariableIsEmpty("QT_QUICK_BACKEND") ||
+            !qEnvironmentVariableIsEmpty("QMLSCENE_DEVICE");
+        if (options.softwareFallback && !envBackend && !qamelOpenGLAvailable()) {
+            qWarning("OpenGL is not available, falling back to software renderer");
+            QQuickWindow::setSceneGraphBackend(QSGRendererInterface::Software);
+        }
+        break;
+    }
+
+    return app;
 }
 
 void App_SetAttribute(long long attribute, bool on) {
@@ -59,4 +125,28 @@ void App_SetOrganizationDomain(char* domain) {
 
 int App_Exec() {
     return QApplication::exec();
-}
\ No newline at end of file
+}
+
+void App_Quit() {
+    QMetaObject::invokeMethod(qApp, "quit", Qt::QueuedConnection);
+}
+
+void App_Exit(int code) {
+    QMetaObject::invokeMethod(qApp, [code]() {
+        QApplication::exit(code);
+    }, Qt::QueuedConnection);
+}
+
+void App_ProcessEvents() {
+    QApplication::processEvents();
+}
+
+char** App_Arguments(int* count) {
+    return qamelCStringArray(QApplication::arguments(), count);
+}
+
+void App_RunOnGuiThread(uintptr_t id) {
+    qamelRunOnGuiThread(qApp, [id]() {
+        qamelRunCallback(id);
+    });
+}
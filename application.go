@@ -7,15 +7,30 @@ package qamel
 // #include "application.h"
 import "C"
 import (
+	"context"
+	"os"
+	"os/signal"
 	"runtime"
-	"strings"
+	"sync"
+	"syscall"
 	"unsafe"
+
+	"github.com/sirupsen/logrus"
 )
 
 func init() {
 	runtime.LockOSThread()
 }
 
+var (
+	guiFuncMutex  = sync.Mutex{}
+	guiFuncLastID = uintptr(0)
+	mapGuiFunc    = map[uintptr]func(){}
+
+	aboutToQuitMutex     = sync.RWMutex{}
+	aboutToQuitCallbacks = []func(){}
+)
+
 // Application is the main app which wraps QGuiApplication
 type Application struct {
 	ptr unsafe.Pointer
@@ -23,12 +38,61 @@ type Application struct {
 
 // NewApplication initializes the window system and constructs
 // an QGuiApplication object with argc command line arguments in argv.
+// If argc is bigger than the length of argv, only argv will be used.
 func NewApplication(argc int, argv []string) *Application {
-	argvC := C.CString(strings.Join(argv, "|"))
-	defer C.free(unsafe.Pointer(argvC))
+	if argc < 0 {
+		argc = 0
+	} else if argc > len(argv) {
+		argc = len(argv)
+	}
+
+	app, _ := NewApplicationWithOptions(argv[:argc], Options{})
+	return app
+}
+
+// NewApplicationWithOptions initializes the window system and constructs an QGuiApplication
+// object with the command line arguments in args and the specified options. The options are
+// validated first, then applied in the order required by Qt: attributes, high-DPI policy and
+// default surface format before the application constructed, then the scene graph backend.
+// It returns error if the options are invalid or BackendOpenGL is used without OpenGL.
+func NewApplicationWithOptions(args []string, opts Options) (*Application, error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+
+	opts.apply()
+
+	cArgs, free := cStringArray(args)
+	defer free()
+
+	cOptions := C.QamelAppOptions{
+		backend:             C.int(opts.Backend),
+		softwareFallback:    C.bool(!opts.NoSoftwareFallback),
+		scaleFactorRounding: C.int(opts.ScaleFactorRounding),
+		samples:             C.int(int32(opts.SurfaceFormat.Samples)),
+		alpha:               C.bool(opts.SurfaceFormat.Alpha),
+	}
 
-	ptr := C.App_NewApplication(C.int(int32(argc)), argvC)
-	return &Application{ptr: ptr}
+	var cError *C.char
+	ptr := C.App_NewApplication(C.int(int32(len(args))), cArgs, cOptions, &cError)
+	if err := goError(cError); err != nil {
+		return nil, err
+	}
+
+	app := &Application{ptr: ptr}
+
+	// Deliver the messages from other instances that received before the app constructed
+	flushInstanceMessages()
+
+	// Register fonts now, so they are available before any QML loaded.
+	// Missing font is not fatal, since the default font could be used.
+	if opts.FontDir != "" {
+		if _, err := AddApplicationFontDir(opts.FontDir); err != nil {
+			logrus.Warnln(err)
+		}
+	}
+
+	return app, nil
 }
 
 // SetAttribute sets the Application's attribute if on is true;
@@ -102,3 +166,107 @@ func (app Application) SetOrganizationDomain(domain string) {
 func (app Application) Exec() int {
 	return int(int32(C.App_Exec()))
 }
+
+// Quit tells the application to exit with return code 0. The exit is queued,
+// so it's safe to call from any goroutine.
+func (app Application) Quit() {
+	C.App_Quit()
+}
+
+// Exit tells the application to exit with the specified return code, which will be
+// returned by Exec. The exit is queued, so it's safe to call from any goroutine.
+func (app Application) Exit(code int) {
+	C.App_Exit(C.int(int32(code)))
+}
+
+// ProcessEvents processes all pending events, e.g. to keep the window responsive while
+// doing long operation. It must be called from the GUI thread.
+func (app Application) ProcessEvents() {
+	C.App_ProcessEvents()
+}
+
+// Arguments returns the command line arguments of the application. Qt removes the
+// arguments that it recognized (e.g. -platform or -style), so the result might be
+// different with the argv that used in NewApplication.
+func (app Application) Arguments() []string {
+	var count C.int
+	cArgs := C.App_Arguments(&count)
+	return goStringSlice(cArgs, count)
+}
+
+// OnAboutToQuit registers callback that will be called when the application is about
+// to quit the main event loop, e.g. to save the settings. The callback is called in the
+// GUI thread, so the windows are still accessible.
+func (app Application) OnAboutToQuit(callback func()) {
+	if callback == nil {
+		return
+	}
+
+	aboutToQuitMutex.Lock()
+	aboutToQuitCallbacks = append(aboutToQuitCallbacks, callback)
+	aboutToQuitMutex.Unlock()
+}
+
+// ExecContext enters the main event loop like Exec. However, the application will quit
+// when ctx is cancelled or when the process receives SIGINT or SIGTERM, so the callbacks
+// that registered in OnAboutToQuit are called properly before the program exits.
+func (app Application) ExecContext(ctx context.Context) int {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			app.Quit()
+		case <-signals:
+			app.Quit()
+		case <-done:
+		}
+	}()
+
+	return app.Exec()
+}
+
+// RunOnGuiThread runs f in the GUI thread and waits until it finished. Qt objects,
+// e.g. Viewer and Engine, must be created in the GUI thread, so use this to create
+// them from other goroutine. If it's called from the GUI thread, f is called directly.
+func RunOnGuiThread(f func()) {
+	if f == nil {
+		return
+	}
+
+	guiFuncMutex.Lock()
+	guiFuncLastID++
+	id := guiFuncLastID
+	mapGuiFunc[id] = f
+	guiFuncMutex.Unlock()
+
+	C.App_RunOnGuiThread(C.uintptr_t(id))
+}
+
+//export qamelRunCallback
+func qamelRunCallback(id C.uintptr_t) {
+	guiFuncMutex.Lock()
+	f := mapGuiFunc[uintptr(id)]
+	delete(mapGuiFunc, uintptr(id))
+	guiFuncMutex.Unlock()
+
+	if f != nil {
+		f()
+	}
+}
+
+//export qamelAboutToQuit
+func qamelAboutToQuit() {
+	aboutToQuitMutex.RLock()
+	callbacks := append([]func(){}, aboutToQuitCallbacks...)
+	aboutToQuitMutex.RUnlock()
+
+	for _, callback := range callbacks {
+		callback()
+	}
+}
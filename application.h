@@ -10,8 +10,16 @@
 extern "C" {
 #endif
 
+typedef struct {
+    int backend;
+    bool softwareFallback;
+    int scaleFactorRounding;
+    int samples;
+    bool alpha;
+} QamelAppOptions;
+
 // Constructor
-void* App_NewApplication(int argc, char* argv);
+void* App_NewApplication(int argc, char** argv, QamelAppOptions options, char** errorMessage);
 
 // Static function
 void App_SetAttribute(long long attribute, bool on);
@@ -26,6 +34,11 @@ void App_SetApplicationVersion(char* version);
 void App_SetOrganizationName(char* name);
 void App_SetOrganizationDomain(char* domain);
 int App_Exec();
+void App_Quit();
+void App_Exit(int code);
+void App_ProcessEvents();
+char** App_Arguments(int* count);
+void App_RunOnGuiThread(uintptr_t id);
 
 #ifdef __cplusplus
 }
@@ -0,0 +1,125 @@
+#include "_cgo_export.h"
+#include "clipboard.h"
+#include "utils.h"
+#include <QGuiApplication>
+#include <QClipboard>
+#include <QMimeData>
+#include <QByteArray>
+#include <QString>
+#include <QImage>
+#include <QVariant>
+
+char* Clipboard_Text() {
+    QString text;
+    qamelRunOnGuiThread(qApp, [&]() { text = QGuiApplication::clipboard()->text(); });
+    return qamelCString(text);
+}
+
+void Clipboard_SetText(char* text) {
+    QString clipboardText(text);
+    qamelRunOnGuiThread(qApp, [&]() { QGuiApplication::clipboard()->setText(clipboardText); });
+}
+
+char* Clipboard_HTML() {
+    QString html;
+    qamelRunOnGuiThread(qApp, [&]() {
+        const QMimeData *mimeData = QGuiApplication::clipboard()->mimeData();
+        if (mimeData != nullptr && mimeData->hasHtml()) {
+            html = mimeData->html();
+        }
+    });
+
+    return qamelCString(html);
+}
+
+void Clipboard_SetHTML(char* html, char* text) {
+    QString clipboardHTML(html);
+    QString clipboardText(text);
+    qamelRunOnGuiThread(qApp, [&]() {
+        QMimeData *mimeData = new QMimeData();
+        mimeData->setHtml(clipboardHTML);
+        mimeData->setText(clipboardText);
+        QGuiApplication::clipboard()->setMimeData(mimeData);
+    });
+}
+
+QamelImage Clipboard_Image() {
+    QImage image;
+    qamelRunOnGuiThread(qApp, [&]() { image = QGuiApplication::clipboard()->image(); });
+    return qamelImage(image);
+}
+
+void Clipboard_SetImage(QamelImage image) {
+    QImage clipboardImage = qamelQImage(image);
+    qamelRunOnGuiThread(qApp, [&]() { QGuiApplication::clipboard()->setImage(clipboardImage); });
+}
+
+char** Clipboard_MimeTypes(int* count) {
+    QStringList mimeTypes;
+    qamelRunOnGuiThread(qApp, [&]() {
+        const QMimeData *mimeData = QGuiApplication::clipboard()->mimeData();
+        if (mimeData != nullptr) {
+            mimeTypes = mimeData->formats();
+        }
+    });
+
+    return qamelCStringArray(mimeTypes, count);
+}
+
+char* Clipboard_Data(char* mimeType, int* length, bool* found) {
+    QString format(mimeType);
+    QByteArray data;
+    *found = false;
+
+    qamelRunOnGuiThread(qApp, [&]() {
+        const QMimeData *mimeData = QGuiApplication::clipboard()->mimeData();
+        if (mimeData != nullptr && mimeData->hasFormat(format)) {
+            data = mimeData->data(format);
+            *found = true;
+        }
+    });
+
+    *length = data.size();
+    if (data.isEmpty()) {
+        return nullptr;
+    }
+
+    char* result = static_cast<char*>(malloc(data.size()));
+    memcpy(result, data.constData(), data.size());
+    return result;
+}
+
+void Clipboard_SetData(char** mimeTypes, char** data, int* lengths, int count) {
+    qamelRunOnGuiThread(qApp, [&]() {
+        QMimeData *mimeData = new QMimeData();
+        for (int i = 0; i < count; i++) {
+            mimeData->setData(QString(mimeTypes[i]), QByteArray(data[i], lengths[i]));
+        }
+        QGuiApplication::clipboard()->setMimeData(mimeData);
+    });
+}
+
+void Clipboard_Clear() {
+    qamelRunOnGuiThread(qApp, [&]() { QGuiApplication::clipboard()->clear(); });
+}
+
+bool Clipboard_Watch() {
+    // Clipboard only exists after the application constructed
+    if (qApp == nullptr) {
+        return false;
+    }
+
+    qamelRunOnGuiThread(qApp, [&]() {
+        QClipboard *clipboard = QGuiApplication::clipboard();
+        if (clipboard->property("_qamelWatched").toBool()) {
+            return;
+        }
+
+        clipboard->setProperty("_qamelWatched", true);
+        QObject::connect(clipboard, &QClipboard::dataChanged, clipboard, []() {
+            qamelClipboardChanged();
+        });
+    });
+
+    return true;
+}
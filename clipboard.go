@@ -0,0 +1,160 @@
+package qamel
+
+// #include <stdlib.h>
+// #include <stdbool.h>
+// #include "clipboard.h"
+import "C"
+import (
+	"image"
+	"sort"
+	"sync"
+	"unsafe"
+)
+
+var (
+	clipboardMutex    = sync.RWMutex{}
+	clipboardCallback func()
+)
+
+// Clipboard is the system clipboard which wraps QClipboard. All of its
+// methods are safe to be called from any goroutine, since they are run
+// in the GUI thread. Therefore, the application must be constructed first.
+type Clipboard struct{}
+
+// Clipboard returns the system clipboard.
+func (app Application) Clipboard() Clipboard {
+	return Clipboard{}
+}
+
+// Text returns the clipboard text as plain text, or an empty
+// string if the clipboard does not contain any text.
+func (cb Clipboard) Text() string {
+	cText := C.Clipboard_Text()
+	defer C.free(unsafe.Pointer(cText))
+	return C.GoString(cText)
+}
+
+// SetText copies text into the clipboard as plain text.
+func (cb Clipboard) SetText(text string) {
+	cText := C.CString(text)
+	defer C.free(unsafe.Pointer(cText))
+	C.Clipboard_SetText(cText)
+}
+
+// HTML returns the clipboard content as HTML, or an empty
+// string if the clipboard does not contain any HTML.
+func (cb Clipboard) HTML() string {
+	cHTML := C.Clipboard_HTML()
+	defer C.free(unsafe.Pointer(cHTML))
+	return C.GoString(cHTML)
+}
+
+// SetHTML copies html into the clipboard. The text is the plain text
+// version of the HTML, which used by applications that don't support HTML.
+func (cb Clipboard) SetHTML(html string, text string) {
+	cHTML := C.CString(html)
+	defer C.free(unsafe.Pointer(cHTML))
+
+	cText := C.CString(text)
+	defer C.free(unsafe.Pointer(cText))
+
+	C.Clipboard_SetHTML(cHTML, cText)
+}
+
+// Image returns the clipboard image, or nil if the clipboard does not contain any image.
+func (cb Clipboard) Image() image.Image {
+	img := goImage(C.Clipboard_Image())
+	if img == nil {
+		return nil
+	}
+
+	return img
+}
+
+// SetImage copies img into the clipboard.
+func (cb Clipboard) SetImage(img image.Image) {
+	cImg, free := cImage(img)
+	defer free()
+	C.Clipboard_SetImage(cImg)
+}
+
+// MimeTypes returns list of MIME types that available in the clipboard.
+func (cb Clipboard) MimeTypes() []string {
+	var count C.int
+	cMimeTypes := C.Clipboard_MimeTypes(&count)
+	return goStringSlice(cMimeTypes, count)
+}
+
+// Data returns the clipboard data with the specified MIME type. The second
+// return value is false if the data with that MIME type is not available.
+func (cb Clipboard) Data(mimeType string) ([]byte, bool) {
+	cMimeType := C.CString(mimeType)
+	defer C.free(unsafe.Pointer(cMimeType))
+
+	var length C.int
+	var found C.bool
+	cData := C.Clipboard_Data(cMimeType, &length, &found)
+	if cData == nil {
+		return nil, bool(found)
+	}
+
+	defer C.free(unsafe.Pointer(cData))
+	return C.GoBytes(unsafe.Pointer(cData), length), true
+}
+
+// SetData replaces the clipboard content with data, which is map of MIME type
+// and its content. Use several MIME types to provide the same content in
+// different formats, e.g. "text/csv" and "text/plain".
+func (cb Clipboard) SetData(data map[string][]byte) {
+	mimeTypes := make([]string, 0, len(data))
+	for mimeType := range data {
+		mimeTypes = append(mimeTypes, mimeType)
+	}
+	sort.Strings(mimeTypes)
+
+	cMimeTypes, freeMimeTypes := cStringArray(mimeTypes)
+	defer freeMimeTypes()
+
+	// The extra element keeps the slices non-empty, so their first element can be passed
+	n := len(mimeTypes)
+	cData := make([]*C.char, n+1)
+	cLengths := make([]C.int, n+1)
+	for i, mimeType := range mimeTypes {
+		cData[i] = (*C.char)(C.CBytes(data[mimeType]))
+		cLengths[i] = C.int(int32(len(data[mimeType])))
+		defer C.free(unsafe.Pointer(cData[i]))
+	}
+
+	C.Clipboard_SetData(cMimeTypes, &cData[0], &cLengths[0], C.int(int32(n)))
+}
+
+// Clear clears the clipboard content.
+func (cb Clipboard) Clear() {
+	C.Clipboard_Clear()
+}
+
+// OnClipboardChanged registers callback that will be called when the clipboard content
+// changed, either by this application or by other application. The callback is called
+// in the GUI thread. Pass nil to remove the callback. It returns ErrNotInitialized if the
+// application is not constructed yet.
+func (cb Clipboard) OnClipboardChanged(callback func()) error {
+	if callback != nil && !bool(C.Clipboard_Watch()) {
+		return ErrNotInitialized
+	}
+
+	clipboardMutex.Lock()
+	clipboardCallback = callback
+	clipboardMutex.Unlock()
+	return nil
+}
+
+//export qamelClipboardChanged
+func qamelClipboardChanged() {
+	clipboardMutex.RLock()
+	callback := clipboardCallback
+	clipboardMutex.RUnlock()
+
+	if callback != nil {
+		callback()
+	}
+}
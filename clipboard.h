@@ -0,0 +1,29 @@
+#pragma once
+
+#ifndef QAMEL_CLIPBOARD_H
+#define QAMEL_CLIPBOARD_H
+
+#include <stdbool.h>
+#include "image.h"
+
+#ifdef __cplusplus
+extern "C" {
+#endif
+
+char* Clipboard_Text();
+void Clipboard_SetText(char* text);
+char* Clipboard_HTML();
+void Clipboard_SetHTML(char* html, char* text);
+QamelImage Clipboard_Image();
+void Clipboard_SetImage(QamelImage image);
+char** Clipboard_MimeTypes(int* count);
+char* Clipboard_Data(char* mimeType, int* length, bool* found);
+void Clipboard_SetData(char** mimeTypes, char** data, int* lengths, int count);
+void Clipboard_Clear();
+bool Clipboard_Watch();
+
+#ifdef __cplusplus
+}
+#endif
+
+#endif
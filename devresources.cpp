@@ -0,0 +1,63 @@
+#include "devresources.h"
+#include <QQmlAbstractUrlInterceptor>
+#include <QString>
+#include <QUrl>
+#include <QDir>
+#include <QFileInfo>
+#include <QMutex>
+#include <QMutexLocker>
+
+// QamelDevInterceptor rewrites URL of resources in qrc:/res/ into
+// the matching file in resource dir inside local file system. The
+// interceptor is shared by all engines, so its dir is guarded by mutex.
+class QamelDevInterceptor : public QQmlAbstractUrlInterceptor {
+public:
+    QamelDevInterceptor(const QString &dirPath) : _dir(dirPath) {}
+
+    void setDir(const QString &dirPath) {
+        QMutexLocker locker(&_mutex);
+        _dir = QDir(dirPath);
+    }
+
+    QUrl intercept(const QUrl &url, QQmlAbstractUrlInterceptor::DataType) override {
+        if (url.scheme() != "qrc" || !url.path().startsWith("/res/")) {
+            return url;
+        }
+
+        QString localPath;
+        {
+            QMutexLocker locker(&_mutex);
+            localPath = _dir.filePath(url.path().mid(5));
+        }
+
+        if (!QFileInfo::exists(localPath)) {
+            return url;
+        }
+
+        QUrl localURL = QUrl::fromLocalFile(localPath);
+        localURL.setQuery(url.query());
+        localURL.setFragment(url.fragment());
+        return localURL;
+    }
+
+private:
+    QMutex _mutex;
+    QDir _dir;
+};
+
+static QamelDevInterceptor *devInterceptor = nullptr;
+
+QQmlAbstractUrlInterceptor* qamelDevInterceptor() {
+    return devInterceptor;
+}
+
+void DevResources_Enable(char* dirPath) {
+    // The interceptor is never deleted, since it's still used by the engines that
+    // created before. Instead, for the next calls only its dir is updated.
+    if (devInterceptor != nullptr) {
+        devInterceptor->setDir(QString(dirPath));
+        return;
+    }
+
+    devInterceptor = new QamelDevInterceptor(QString(dirPath));
+}
@@ -0,0 +1,39 @@
+package qamel
+
+// #include <stdlib.h>
+// #include "devresources.h"
+import "C"
+import (
+	"fmt"
+	"os"
+	fp "path/filepath"
+	"unsafe"
+)
+
+// EnableDevResources enables development mode for Qt resources. In this mode, every
+// resource in qrc:/res/ that loaded by QML engine will be redirected to the matching
+// file inside dirPath in local file system, as long as the file exists. This way
+// the app can use qrc URL in both development and release, while still be able to
+// use live reload with WatchResourceDir in development. In this mode, Engine will
+// also show a window that lists the QML errors when the QML fails to load.
+//
+// The dirPath must be absolute path to the resource dir, e.g. "/path/to/project/res".
+// EnableDevResources must be called before creating Viewer or Engine, since the
+// redirection is only installed to the engines created after it. Calling it again
+// changes the resource dir for all engines that use the redirection.
+// Only use this in development environment.
+func EnableDevResources(dirPath string) error {
+	if !fp.IsAbs(dirPath) {
+		return fmt.Errorf("path to directory must be absolute")
+	}
+
+	dirInfo, err := os.Stat(dirPath)
+	if err != nil || !dirInfo.IsDir() {
+		return fmt.Errorf("directory %s does not exist", dirPath)
+	}
+
+	cDirPath := C.CString(dirPath)
+	defer C.free(unsafe.Pointer(cDirPath))
+	C.DevResources_Enable(cDirPath)
+	return nil
+}
@@ -0,0 +1,23 @@
+#pragma once
+
+#ifndef QAMEL_DEVRESOURCES_H
+#define QAMEL_DEVRESOURCES_H
+
+#ifdef __cplusplus
+
+#include <QQmlAbstractUrlInterceptor>
+
+// qamelDevInterceptor returns the URL interceptor for development resources,
+// or nullptr if development resources is not enabled.
+QQmlAbstractUrlInterceptor* qamelDevInterceptor();
+
+extern "C" {
+#endif
+
+void DevResources_Enable(char* dirPath);
+
+#ifdef __cplusplus
+}
+#endif
+
+#endif
@@ -1,18 +1,294 @@
 #include "engine.h"
+#include "utils.h"
+#include "handle.h"
+#include "devresources.h"
+#include "hotreload.h"
+#include "overlay.h"
+#include "window.h"
+#include "fileselector.h"
 #include <QQmlApplicationEngine>
+#include <QByteArray>
 #include <QString>
 #include <QUrl>
+#include <QList>
+#include <QHash>
+#include <QStringList>
+#include <QWindow>
+#include <QQuickWindow>
+#include <QQmlError>
+#include <QPointer>
+#include <QMetaObject>
+#include <QGuiApplication>
+
+class QamelEngine : public QQmlApplicationEngine {
+    Q_OBJECT
+
+public:
+    QamelEngine(QObject *parent = nullptr) : QQmlApplicationEngine(parent) {
+        qamelInstallDevInterceptor(this);
+
+        // Collect the errors of the current load. The engine reports a failed load,
+        // both on compile and on creation, by emitting objectCreated with nullptr.
+        connect(this, &QQmlEngine::warnings, this, [this](const QList<QQmlError> &warnings) {
+            if (_loading) {
+                _loadErrors.append(warnings);
+            }
+        });
+
+        connect(this, &QQmlApplicationEngine::objectCreated, this, [this](QObject *obj, const QUrl &) {
+            if (obj == nullptr) {
+                _loadFailed = true;
+                return;
+            }
+
+            // Track normal geometry of the windows, so it's restored after reload
+            qamelTrackNormalGeometry(qobject_cast<QWindow*>(obj));
+        });
+    }
+
+    ~QamelEngine() {
+        delete _errorWindow;
+    }
+
+    void loadURL(const QUrl &url) {
+        _lastURL = url;
+        _lastData.clear();
+        loadLast(qamelDevInterceptor() != nullptr);
+    }
+
+    QList<QQmlError> loadQmlData(const QByteArray &data, const QUrl &url) {
+        _lastURL = url;
+        _lastData = data;
+        return loadLast(qamelDevInterceptor() != nullptr);
+    }
+
+    void preserveProperties(const QString &objectName, const QStringList &properties) {
+        if (properties.isEmpty()) {
+            _preserved.remove(objectName);
+        } else {
+            _preserved.insert(objectName, properties);
+        }
+    }
+
+public slots:
+    void reload() {
+        if (_lastURL.isEmpty()) {
+            return;
+        }
+
+        // Save state of the old windows, then destroy them. If there are no
+        // windows (e.g. the previous reload failed), keep the last saved state.
+        QList<QObject*> oldObjects = rootObjects();
+        if (!oldObjects.isEmpty()) {
+            _snapshot.capture(oldObjects, _preserved);
+            _windowStates.clear();
+            for (QObject *obj : oldObjects) {
+                _windowStates.append(QamelWindowState::capture(qobject_cast<QWindow*>(obj)));
+                delete obj;
+            }
+        }
+
+        // Reload the last loaded source
+        clearComponentCache();
+        if (!loadLast(qamelDevInterceptor() != nullptr).isEmpty()) {
+            return;
+        }
+
+        // Restore state to the new windows
+        QList<QObject*> objects = rootObjects();
+        _snapshot.restore(objects);
+        for (int i = 0; i < objects.size() && i < _windowStates.size(); i++) {
+            _windowStates.at(i).restore(qobject_cast<QWindow*>(objects.at(i)));
+        }
+    }
+
+private:
+    // loadLast loads the last loaded source. If the source can't be compiled or created,
+    // the errors are returned and, if showErrors is true, shown in the error window.
+    // Remote source is loaded asynchronously, so its errors are not reported here.
+    QList<QQmlError> loadLast(bool showErrors) {
+        delete _errorWindow;
+
+        _loading = true;
+        _loadFailed = false;
+        _loadErrors.clear();
+
+        if (_lastData.isEmpty()) {
+            load(_lastURL);
+        } else {
+            loadData(_lastData, _lastURL);
+        }
+
+        _loading = false;
+        if (!_loadFailed) {
+            return QList<QQmlError>();
+        }
+
+        QList<QQmlError> errors = _loadErrors;
+        if (errors.isEmpty()) {
+            QQmlError error;
+            error.setUrl(_lastURL);
+            error.setDescription("failed to load component");
+            errors.append(error);
+        }
+
+        if (showErrors) {
+            showErrorWindow(errors);
+        }
+        return errors;
+    }
+
+    void showErrorWindow(const QList<QQmlError> &errors) {
+        delete _errorWindow;
+        _errorWindow = qamelCreateErrorWindow(this, errors);
+        if (_errorWindow == nullptr) {
+            return;
+        }
+
+        if (!_windowStates.isEmpty()) {
+            _windowStates.first().restore(_errorWindow);
+        }
+        _errorWindow->show();
+    }
+
+    QUrl _lastURL;
+    QByteArray _lastData;
+    QHash<QString, QStringList> _preserved;
+    QamelStateSnapshot _snapshot;
+    QList<QamelWindowState> _windowStates;
+    QPointer<QQuickWindow> _errorWindow;
+    bool _loading = false;
+    bool _loadFailed = false;
+    QList<QQmlError> _loadErrors;
+};
 
 void* Engine_NewEngine() {
-    return new QQmlApplicationEngine();
+    return new QamelEngine();
+}
+
+void Engine_Close(void* ptr) {
+    QamelEngine *engine = static_cast<QamelEngine*>(ptr);
+    qamelRunOnGuiThread(qApp, [&]() {
+        for (QObject *obj : engine->rootObjects()) {
+            if (QWindow *window = qobject_cast<QWindow*>(obj)) {
+                window->close();
+            }
+        }
+        engine->deleteLater();
+    });
+}
+
+void Engine_Destroy(void* ptr) {
+    QamelEngine *engine = static_cast<QamelEngine*>(ptr);
+    engine->deleteLater();
+}
+
+bool Engine_Load(void* ptr, uint64_t id, char* url) {
+    QUrl sourceURL = QUrl(QString(url));
+    return qamelRunOnHandle<QamelEngine>(ptr, id, [&](QamelEngine *engine) {
+        engine->loadURL(sourceURL);
+    });
+}
+
+char* Engine_LoadData(void* ptr, uint64_t id, char* data, int length, char* baseURL, bool* live) {
+    QByteArray qmlData(data, length);
+    QUrl url = QUrl(QString(baseURL));
+
+    char* errors = nullptr;
+    *live = qamelRunOnHandle<QamelEngine>(ptr, id, [&](QamelEngine *engine) {
+        errors = qamelQmlErrors(engine->loadQmlData(qmlData, url));
+    });
+
+    return errors;
+}
+
+bool Engine_ClearComponentCache(void* ptr, uint64_t id) {
+    return qamelRunOnHandle<QamelEngine>(ptr, id, [&](QamelEngine *engine) {
+        engine->clearComponentCache();
+    });
 }
 
-void Engine_Load(void* ptr, char* url) {
-    QQmlApplicationEngine *engine = static_cast<QQmlApplicationEngine*>(ptr);
-    engine->load(QUrl(QString(url)));
+bool Engine_AddImportPath(void* ptr, uint64_t id, char* path) {
+    QString importPath(path);
+    return qamelRunOnHandle<QamelEngine>(ptr, id, [&](QamelEngine *engine) {
+        engine->addImportPath(importPath);
+    });
 }
 
-void Engine_ClearComponentCache(void* ptr) {
-    QQmlApplicationEngine *engine = static_cast<QQmlApplicationEngine*>(ptr);
-    engine->clearComponentCache();
+bool Engine_SetImportPathList(void* ptr, uint64_t id, char** paths, int count) {
+    QStringList list = qamelStringList(paths, count);
+    return qamelRunOnHandle<QamelEngine>(ptr, id, [&](QamelEngine *engine) {
+        engine->setImportPathList(list);
+    });
 }
+
+char** Engine_ImportPathList(void* ptr, uint64_t id, int* count) {
+    QStringList list;
+    qamelRunOnHandle<QamelEngine>(ptr, id, [&](QamelEngine *engine) {
+        list = engine->importPathList();
+    });
+    return qamelCStringArray(list, count);
+}
+
+bool Engine_AddPluginPath(void* ptr, uint64_t id, char* path) {
+    QString pluginPath(path);
+    return qamelRunOnHandle<QamelEngine>(ptr, id, [&](QamelEngine *engine) {
+        engine->addPluginPath(pluginPath);
+    });
+}
+
+bool Engine_SetOfflineStoragePath(void* ptr, uint64_t id, char* path) {
+    QString storagePath(path);
+    return qamelRunOnHandle<QamelEngine>(ptr, id, [&](QamelEngine *engine) {
+        engine->setOfflineStoragePath(storagePath);
+    });
+}
+
+bool Engine_SetBaseURL(void* ptr, uint64_t id, char* url) {
+    QUrl baseURL = QUrl(QString(url));
+    return qamelRunOnHandle<QamelEngine>(ptr, id, [&](QamelEngine *engine) {
+        engine->setBaseUrl(baseURL);
+    });
+}
+
+bool Engine_SetFileSelectors(void* ptr, uint64_t id, char** selectors, int count) {
+    QStringList list = qamelStringList(selectors, count);
+    return qamelRunOnHandle<QamelEngine>(ptr, id, [&](QamelEngine *engine) {
+        qamelSetFileSelectors(engine, list);
+    });
+}
+
+QamelWindowHandle* Engine_Windows(void* ptr, uint64_t id, int* count) {
+    QamelWindowHandle* result = nullptr;
+    *count = 0;
+
+    qamelRunOnHandle<QamelEngine>(ptr, id, [&](QamelEngine *engine) {
+        QList<QQuickWindow*> windows;
+        for (QObject *obj : engine->rootObjects()) {
+            if (QQuickWindow *window = qobject_cast<QQuickWindow*>(obj)) {
+                windows.append(window);
+            }
+        }
+
+        result = qamelWindowHandles(windows, count);
+    });
+
+    return result;
+}
+
+bool Engine_PreserveProperties(void* ptr, uint64_t id, char* objectName, char** properties, int count) {
+    QString name(objectName);
+    QStringList list = qamelStringList(properties, count);
+    return qamelRunOnHandle<QamelEngine>(ptr, id, [&](QamelEngine *engine) {
+        engine->preserveProperties(name, list);
+    });
+}
+
+bool Engine_Reload(void* ptr, uint64_t id) {
+    // The reload is queued to the engine, so it's dropped if the engine deleted in the meantime
+    return qamelRunOnHandle<QamelEngine>(ptr, id, [&](QamelEngine *engine) {
+        QMetaObject::invokeMethod(engine, "reload", Qt::QueuedConnection);
+    });
+}
+
+#include "moc-engine.h"
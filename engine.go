@@ -6,18 +6,22 @@ package qamel
 // #include <stdbool.h>
 // #include "engine.h"
 import "C"
-import "unsafe"
+import (
+	"context"
+	"unsafe"
+)
 
 // Engine is the wrapper for QQMLApplicationEngine
 type Engine struct {
 	ptr unsafe.Pointer
+	id  uint64
 }
 
 // NewEngine creates a new QQmlApplicationEngine with the given parent.
 // You will have to call load() later in order to load a QML file.
 func NewEngine() Engine {
 	ptr := C.Engine_NewEngine()
-	return Engine{ptr: ptr}
+	return Engine{ptr: ptr, id: registerHandle(ptr, engineHandle)}
 }
 
 // NewEngineWithSource constructs a QQmlApplicationEngine with the given QML source.
@@ -27,16 +31,62 @@ func NewEngineWithSource(source string) Engine {
 	return engine
 }
 
-// Load loads the root QML file located at url. The object tree defined by the file is
-// created immediately for local file urls.
-func (engine Engine) Load(url string) {
-	if engine.ptr == nil {
-		return
+// err returns the error if the native engine is not live anymore.
+func (engine Engine) err() error {
+	return handleError(engine.ptr, engine.id)
+}
+
+// Close closes all root windows of the engine, then destroys the engine like Destroy.
+func (engine Engine) Close() error {
+	if !releaseHandle(engine.ptr, engine.id) {
+		return engine.err()
+	}
+
+	C.Engine_Close(engine.ptr)
+	return nil
+}
+
+// Destroy deletes the native engine and all of its root objects in the GUI thread.
+// After this, the engine and all of its copies become invalid: methods that return
+// error will return ErrDestroyed and the getters return zero values. The resource
+// watchers of the engine are released.
+func (engine Engine) Destroy() error {
+	if !releaseHandle(engine.ptr, engine.id) {
+		return engine.err()
 	}
 
+	C.Engine_Destroy(engine.ptr)
+	return nil
+}
+
+// Load loads the root QML file located at url. The object tree defined by the file is
+// created immediately for local file urls.
+func (engine Engine) Load(url string) error {
 	cURL := C.CString(url)
 	defer C.free(unsafe.Pointer(cURL))
-	C.Engine_Load(engine.ptr, cURL)
+	return nativeError(engine.ptr, C.Engine_Load(engine.ptr, C.uint64_t(engine.id), cURL))
+}
+
+// LoadData loads the QML given in data. The object tree defined by data is instantiated
+// immediately. The baseURL is used as the URL of the component, so relative imports and
+// URLs inside the QML will be resolved against it. If data can't be compiled, the returned
+// error contains the list of QML errors.
+func (engine Engine) LoadData(data []byte, baseURL string) error {
+	cData := C.CBytes(data)
+	cBaseURL := C.CString(baseURL)
+	defer func() {
+		C.free(cData)
+		C.free(unsafe.Pointer(cBaseURL))
+	}()
+
+	var live C.bool
+	cErrors := C.Engine_LoadData(engine.ptr, C.uint64_t(engine.id),
+		(*C.char)(cData), C.int(int32(len(data))), cBaseURL, &live)
+	if err := nativeError(engine.ptr, live); err != nil {
+		return err
+	}
+
+	return qmlError(cErrors)
 }
 
 // ClearComponentCache clears the engine's internal component cache. This function causes the property
@@ -46,10 +96,129 @@ func (engine Engine) Load(url string) {
 // component data. This may be useful in order to reload a smaller subset of the previous component set,
 // or to load a new version of a previously loaded component. Once the component cache has been cleared,
 // components must be loaded before any new objects can be created.
-func (engine Engine) ClearComponentCache() {
-	if engine.ptr == nil {
-		return
+func (engine Engine) ClearComponentCache() error {
+	return nativeError(engine.ptr, C.Engine_ClearComponentCache(engine.ptr, C.uint64_t(engine.id)))
+}
+
+// AddImportPath adds path as a directory where the engine searches for installed modules
+// in a URL-based directory structure. The path may be a local filesystem directory, a Qt
+// Resource path (:/imports), a Qt Resource url (qrc:/imports) or a URL.
+func (engine Engine) AddImportPath(path string) error {
+	cPath := C.CString(path)
+	defer C.free(unsafe.Pointer(cPath))
+	return nativeError(engine.ptr, C.Engine_AddImportPath(engine.ptr, C.uint64_t(engine.id), cPath))
+}
+
+// SetImportPathList sets paths as the list of directories where the engine searches for
+// installed modules in a URL-based directory structure. By default, the list contains
+// the directory of the application executable, paths specified in the QML2_IMPORT_PATH
+// environment variable, and the builtin Qml2ImportsPath from QLibraryInfo.
+func (engine Engine) SetImportPathList(paths []string) error {
+	cPaths, free := cStringArray(paths)
+	defer free()
+	return nativeError(engine.ptr, C.Engine_SetImportPathList(engine.ptr, C.uint64_t(engine.id), cPaths, C.int(int32(len(paths)))))
+}
+
+// ImportPathList returns the list of directories where the engine searches for installed
+// modules in a URL-based directory structure.
+func (engine Engine) ImportPathList() []string {
+	var count C.int
+	cPaths := C.Engine_ImportPathList(engine.ptr, C.uint64_t(engine.id), &count)
+	return goStringSlice(cPaths, count)
+}
+
+// SetFileSelectors sets the extra selectors that used to choose the variant of QML files.
+// For example, with selector "kiosk", the file +kiosk/Main.qml will be loaded instead of
+// Main.qml if it exists. Selectors are matched in order, and the leading "+" is optional.
+// It must be called before loading the QML, e.g. at startup from the app config.
+func (engine Engine) SetFileSelectors(selectors []string) error {
+	selectors = cleanFileSelectors(selectors)
+	cSelectors, free := cStringArray(selectors)
+	defer free()
+	return nativeError(engine.ptr, C.Engine_SetFileSelectors(engine.ptr, C.uint64_t(engine.id), cSelectors, C.int(int32(len(selectors)))))
+}
+
+// AddPluginPath adds path as a directory where the engine searches for native plugins for
+// imported modules (referenced in the qmldir file). By default, the list contains only ".",
+// i.e. the engine searches in the directory of the qmldir file itself.
+func (engine Engine) AddPluginPath(path string) error {
+	cPath := C.CString(path)
+	defer C.free(unsafe.Pointer(cPath))
+	return nativeError(engine.ptr, C.Engine_AddPluginPath(engine.ptr, C.uint64_t(engine.id), cPath))
+}
+
+// SetOfflineStoragePath sets the directory for storing offline user data. This is where
+// the data from QML's LocalStorage is saved.
+func (engine Engine) SetOfflineStoragePath(path string) error {
+	cPath := C.CString(path)
+	defer C.free(unsafe.Pointer(cPath))
+	return nativeError(engine.ptr, C.Engine_SetOfflineStoragePath(engine.ptr, C.uint64_t(engine.id), cPath))
+}
+
+// SetBaseURL sets the base URL for this engine. The base URL is only used to resolve
+// components when a relative URL is passed to the QQmlComponent constructor. If a base
+// URL is not specified, the current working directory is used.
+func (engine Engine) SetBaseURL(url string) error {
+	cURL := C.CString(url)
+	defer C.free(unsafe.Pointer(cURL))
+	return nativeError(engine.ptr, C.Engine_SetBaseURL(engine.ptr, C.uint64_t(engine.id), cURL))
+}
+
+// Reload destroys the root objects of the engine, clears the component cache and loads
+// the last loaded QML source again. The geometry of the root windows will be kept.
+// If the QML fails to load while dev resources enabled, a window that lists the QML errors
+// will be shown in place of the root windows, and it will be closed on the next successful reload.
+// The reload is queued and done in the GUI thread, so it's safe to call from any goroutine.
+func (engine Engine) Reload() error {
+	return nativeError(engine.ptr, C.Engine_Reload(engine.ptr, C.uint64_t(engine.id)))
+}
+
+// PreserveProperties marks properties of the QML object with the specified objectName to be
+// preserved across reload. Before reload, the value of those properties are saved, then
+// restored to the object with the same objectName once the QML is loaded again. This is
+// useful to keep navigation state, text input and scroll position during live reload.
+// Calling it without properties removes the mark for that object. The properties can
+// also be marked from QML by declaring `property var qamelPreserve: ["text", "contentY"]`
+// in an object that has objectName. The object is matched by its path, i.e. the objectName
+// of itself and its named ancestors, so objects that share the same path are skipped.
+// Window geometry and state are always preserved.
+func (engine Engine) PreserveProperties(objectName string, properties ...string) error {
+	cObjectName := C.CString(objectName)
+	cProperties, free := cStringArray(properties)
+	defer func() {
+		C.free(unsafe.Pointer(cObjectName))
+		free()
+	}()
+
+	return nativeError(engine.ptr, C.Engine_PreserveProperties(engine.ptr, C.uint64_t(engine.id), cObjectName, cProperties, C.int(int32(len(properties)))))
+}
+
+// WatchResourceDir watches for change inside the specified resource dir and its subdirs,
+// including the ones created later. When change happened, the engine will be reloaded in
+// the GUI thread. The directory path must be absolute. WatchResourceDir blocks until ctx
+// is cancelled, so usually it's run in its own goroutine. It returns error if the watcher
+// can't be started. Only use this in development environment.
+func (engine Engine) WatchResourceDir(ctx context.Context, dirPath string, opts WatchOptions) error {
+	if err := engine.err(); err != nil {
+		return err
 	}
 
-	C.Engine_ClearComponentCache(engine.ptr)
+	// Stop watching when the engine destroyed
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	removeCleanup, ok := addHandleCleanup(engine.ptr, engine.id, cancel)
+	if !ok {
+		return ErrDestroyed
+	}
+	defer removeCleanup()
+
+	return watchResourceDir(ctx, dirPath, opts, func() { engine.Reload() })
+}
+
+// Windows returns the top level QML windows that created by the engine.
+func (engine Engine) Windows() []Window {
+	var count C.int
+	cWindows := C.Engine_Windows(engine.ptr, C.uint64_t(engine.id), &count)
+	return goWindows(cWindows, count)
 }
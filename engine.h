@@ -3,16 +3,39 @@
 #ifndef QAMEL_ENGINE_H
 #define QAMEL_ENGINE_H
 
+#include <stdint.h>
+#include <stdbool.h>
+#include "window.h"
+
 #ifdef __cplusplus
+
+// Class
+class QamelEngine;
+
 extern "C" {
 #endif
 
 // Constructors
 void* Engine_NewEngine();
 
+// Destructors
+void Engine_Close(void* ptr);
+void Engine_Destroy(void* ptr);
+
 // Methods
-void Engine_Load(void* ptr, char* url);
-void Engine_ClearComponentCache(void* ptr);
+bool Engine_Load(void* ptr, uint64_t id, char* url);
+char* Engine_LoadData(void* ptr, uint64_t id, char* data, int length, char* baseURL, bool* live);
+bool Engine_ClearComponentCache(void* ptr, uint64_t id);
+bool Engine_Reload(void* ptr, uint64_t id);
+bool Engine_PreserveProperties(void* ptr, uint64_t id, char* objectName, char** properties, int count);
+bool Engine_AddImportPath(void* ptr, uint64_t id, char* path);
+bool Engine_SetImportPathList(void* ptr, uint64_t id, char** paths, int count);
+char** Engine_ImportPathList(void* ptr, uint64_t id, int* count);
+bool Engine_AddPluginPath(void* ptr, uint64_t id, char* path);
+bool Engine_SetOfflineStoragePath(void* ptr, uint64_t id, char* path);
+bool Engine_SetBaseURL(void* ptr, uint64_t id, char* url);
+QamelWindowHandle* Engine_Windows(void* ptr, uint64_t id, int* count);
+bool Engine_SetFileSelectors(void* ptr, uint64_t id, char** selectors, int count);
 
 #ifdef __cplusplus
 }
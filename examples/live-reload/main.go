@@ -1,6 +1,8 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"log"
 	"os"
 	fp "path/filepath"
@@ -9,26 +11,46 @@ import (
 )
 
 func main() {
+	// Parse flags
+	devMode := flag.Bool("dev", false, "load resources from disk and enable live reload")
+	flag.Parse()
+
 	// Create application
 	app := qamel.NewApplication(len(os.Args), os.Args)
 	app.SetApplicationDisplayName("Live Reload Example")
 
+	// In development mode, load resources from res dir instead of the embedded ones
+	projectDir, err := os.Getwd()
+	if err != nil {
+		log.Fatalln("Failed to get working directory:", err)
+	}
+
+	resDir := fp.Join(projectDir, "res")
+	if *devMode {
+		if err = qamel.EnableDevResources(resDir); err != nil {
+			log.Fatalln("Failed to enable dev resources:", err)
+		}
+	}
+
 	// Create a QML viewer
-	view := qamel.NewViewerWithSource("res/main.qml")
+	view := qamel.NewViewerWithSource("qrc:/res/main.qml")
 	view.SetResizeMode(qamel.SizeRootObjectToView)
 	view.SetHeight(300)
 	view.SetWidth(400)
 	view.Show()
 
 	// Watch change in resource dir
-	projectDir, err := os.Getwd()
-	if err != nil {
-		log.Fatalln("Failed to get working directory:", err)
+	if *devMode {
+		go func() {
+			err := view.WatchResourceDir(context.Background(), resDir, qamel.WatchOptions{
+				Include: []string{"*.qml", "*.js"},
+			})
+			if err != nil {
+				log.Println("Failed to watch resource dir:", err)
+			}
+		}()
 	}
 
-	resDir := fp.Join(projectDir, "res")
-	go view.WatchResourceDir(resDir)
-
 	// Exec app
 	app.Exec()
 }
@@ -0,0 +1,91 @@
+#include "fileselector.h"
+#include "devresources.h"
+#include <QObject>
+#include <QQmlEngine>
+#include <QQmlFileSelector>
+#include <QFileSelector>
+#include <QQmlAbstractUrlInterceptor>
+#include <QStringList>
+#include <QUrl>
+
+// QamelSelectorInterceptor selects the file for the URL using selector, like the
+// interceptor that installed by QQmlFileSelector.
+class QamelSelectorInterceptor : public QObject, public QQmlAbstractUrlInterceptor {
+public:
+    QamelSelectorInterceptor(QFileSelector *selector, QObject *parent)
+        : QObject(parent), _selector(selector) {}
+
+    QUrl intercept(const QUrl &url, QQmlAbstractUrlInterceptor::DataType type) override {
+        // Like QQmlFileSelector, qmldir files are not selected
+        if (type == QQmlAbstractUrlInterceptor::QmldirFile) {
+            return url;
+        }
+
+        return _selector->select(url);
+    }
+
+private:
+    QFileSelector *_selector;
+};
+
+// QamelChainInterceptor calls several URL interceptors in order, since
+// QQmlEngine in Qt 5 only allows one interceptor to be installed.
+class QamelChainInterceptor : public QObject, public QQmlAbstractUrlInterceptor {
+public:
+    QamelChainInterceptor(const QList<QQmlAbstractUrlInterceptor*> &interceptors, QObject *parent)
+        : QObject(parent), _interceptors(interceptors) {}
+
+    QUrl intercept(const QUrl &url, QQmlAbstractUrlInterceptor::DataType type) override {
+        QUrl result = url;
+        for (QQmlAbstractUrlInterceptor *interceptor : _interceptors) {
+            result = interceptor->intercept(result, type);
+        }
+
+        return result;
+    }
+
+private:
+    QList<QQmlAbstractUrlInterceptor*> _interceptors;
+};
+
+void qamelInstallDevInterceptor(QQmlEngine *engine) {
+    QQmlAbstractUrlInterceptor *devInterceptor = qamelDevInterceptor();
+    if (devInterceptor == nullptr) {
+        return;
+    }
+
+    // Keep the file selection of QQmlFileSelector (e.g. the one that QQmlApplicationEngine
+    // has by default), so the same QML is loaded as in release build.
+    QQmlFileSelector *fileSelector = QQmlFileSelector::get(engine);
+    if (fileSelector == nullptr) {
+        engine->setUrlInterceptor(devInterceptor);
+        return;
+    }
+
+    QamelSelectorInterceptor *selectorInterceptor = new QamelSelectorInterceptor(fileSelector->selector(), engine);
+    engine->setUrlInterceptor(new QamelChainInterceptor({devInterceptor, selectorInterceptor}, engine));
+}
+
+void qamelSetFileSelectors(QQmlEngine *engine, const QStringList &selectors) {
+    // QQmlApplicationEngine already has QQmlFileSelector, but its interceptor may have been
+    // replaced by the interceptor that installed before. The interceptor of QQmlFileSelector
+    // is private, so select the file using its QFileSelector instead. If the chain already
+    // installed, e.g. by qamelInstallDevInterceptor, it already selects the file.
+    QQmlFileSelector *fileSelector = QQmlFileSelector::get(engine);
+    if (engine->findChild<QamelChainInterceptor*>(QString(), Qt::FindDirectChildrenOnly) == nullptr) {
+        QQmlAbstractUrlInterceptor *oldInterceptor = engine->urlInterceptor();
+        if (fileSelector == nullptr) {
+            fileSelector = new QQmlFileSelector(engine, engine);
+        }
+
+        // Selecting the file twice is harmless, in case the old interceptor is QQmlFileSelector
+        QList<QQmlAbstractUrlInterceptor*> interceptors;
+        if (oldInterceptor != nullptr) {
+            interceptors.append(oldInterceptor);
+        }
+        interceptors.append(new QamelSelectorInterceptor(fileSelector->selector(), engine));
+        engine->setUrlInterceptor(new QamelChainInterceptor(interceptors, engine));
+    }
+
+    fileSelector->setExtraSelectors(selectors);
+}
@@ -0,0 +1,23 @@
+#pragma once
+
+#ifndef QAMEL_FILESELECTOR_H
+#define QAMEL_FILESELECTOR_H
+
+#ifdef __cplusplus
+
+#include <QQmlEngine>
+#include <QStringList>
+
+// qamelInstallDevInterceptor installs the URL interceptor for development resources in the
+// engine, if it's enabled. The file selection of QQmlFileSelector in the engine is kept.
+void qamelInstallDevInterceptor(QQmlEngine *engine);
+
+// qamelSetFileSelectors installs QQmlFileSelector in the engine (once) and sets its extra
+// selectors, so e.g. +kiosk/Main.qml is used instead of Main.qml when "kiosk" is selected.
+// The URL interceptor that already installed in the engine, e.g. the one for development
+// resources, is kept and called first.
+void qamelSetFileSelectors(QQmlEngine *engine, const QStringList &selectors);
+
+#endif // __cplusplus
+
+#endif // QAMEL_FILESELECTOR_H
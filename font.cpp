@@ -0,0 +1,63 @@
+#include "font.h"
+#include "utils.h"
+#include <QGuiApplication>
+#include <QFontDatabase>
+#include <QByteArray>
+#include <QDirIterator>
+#include <QString>
+#include <QStringList>
+
+char** Font_AddApplicationFont(char* fileName, int* count) {
+    QString path(fileName);
+    QStringList families;
+    bool ok = false;
+
+    qamelRunOnGuiThread(qApp, [&]() {
+        int id = QFontDatabase::addApplicationFont(path);
+        if (id >= 0) {
+            families = QFontDatabase::applicationFontFamilies(id);
+            ok = true;
+        }
+    });
+
+    if (!ok) {
+        *count = -1;
+        return nullptr;
+    }
+
+    return qamelCStringArray(families, count);
+}
+
+char** Font_AddApplicationFontFromData(char* data, int length, int* count) {
+    QByteArray fontData(data, length);
+    QStringList families;
+    bool ok = false;
+
+    qamelRunOnGuiThread(qApp, [&]() {
+        int id = QFontDatabase::addApplicationFontFromData(fontData);
+        if (id >= 0) {
+            families = QFontDatabase::applicationFontFamilies(id);
+            ok = true;
+        }
+    });
+
+    if (!ok) {
+        *count = -1;
+        return nullptr;
+    }
+
+    return qamelCStringArray(families, count);
+}
+
+char** Font_FontFiles(char* dirPath, int* count) {
+    QStringList nameFilters = {"*.ttf", "*.otf", "*.ttc", "*.otc"};
+    QStringList files;
+
+    QDirIterator it(QString(dirPath), nameFilters, QDir::Files, QDirIterator::Subdirectories);
+    while (it.hasNext()) {
+        files.append(it.next());
+    }
+
+    files.sort();
+    return qamelCStringArray(files, count);
+}
@@ -0,0 +1,91 @@
+package qamel
+
+// #include <stdlib.h>
+// #include "font.h"
+import "C"
+import (
+	"fmt"
+	"strings"
+	"unsafe"
+)
+
+// AddApplicationFont loads the font from the file specified by fileName and makes it
+// available to the application. The fileName could be a file path or Qt resource path
+// (qrc:/res/fonts/font.ttf). It returns the font families that contained in the file,
+// which can be used in SetFont or in QML font.family. The application must be constructed
+// before calling this function.
+func AddApplicationFont(fileName string) ([]string, error) {
+	cFileName := C.CString(localPath(fileName))
+	defer C.free(unsafe.Pointer(cFileName))
+
+	var count C.int
+	cFamilies := C.Font_AddApplicationFont(cFileName, &count)
+	if count < 0 {
+		return nil, fmt.Errorf("failed to load font %s", fileName)
+	}
+
+	return goStringSlice(cFamilies, count), nil
+}
+
+// AddApplicationFontFromData loads the font from binary data and makes it available to
+// the application. It returns the font families that contained in the data. The
+// application must be constructed before calling this function.
+func AddApplicationFontFromData(data []byte) ([]string, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("font data is empty")
+	}
+
+	cData := C.CBytes(data)
+	defer C.free(cData)
+
+	var count C.int
+	cFamilies := C.Font_AddApplicationFontFromData((*C.char)(cData), C.int(int32(len(data))), &count)
+	if count < 0 {
+		return nil, fmt.Errorf("failed to load font from data")
+	}
+
+	return goStringSlice(cFamilies, count), nil
+}
+
+// AddApplicationFontDir loads all fonts (*.ttf, *.otf, *.ttc and *.otc) inside the
+// directory and its subdirectories. The dirPath could be a directory path or Qt resource
+// path, e.g. qrc:/res/fonts. It returns the font families that registered, or error if
+// there are no fonts inside the directory. If some fonts
+// failed to load, the other fonts are still registered and the error is returned.
+func AddApplicationFontDir(dirPath string) ([]string, error) {
+	cDirPath := C.CString(localPath(dirPath))
+	defer C.free(unsafe.Pointer(cDirPath))
+
+	var count C.int
+	cFiles := C.Font_FontFiles(cDirPath, &count)
+	files := goStringSlice(cFiles, count)
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no fonts found in %s", dirPath)
+	}
+
+	var families, failed []string
+	for _, file := range files {
+		fileFamilies, err := AddApplicationFont(file)
+		if err != nil {
+			failed = append(failed, file)
+			continue
+		}
+		families = append(families, fileFamilies...)
+	}
+
+	if len(failed) > 0 {
+		return families, fmt.Errorf("failed to load fonts: %s", strings.Join(failed, ", "))
+	}
+
+	return families, nil
+}
+
+// localPath converts Qt resource URL (qrc:/path) into
+// resource path (:/path) that used by Qt file API.
+func localPath(path string) string {
+	if strings.HasPrefix(path, "qrc:") {
+		return ":/" + strings.TrimLeft(strings.TrimPrefix(path, "qrc:"), "/")
+	}
+
+	return path
+}
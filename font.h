@@ -0,0 +1,18 @@
+#pragma once
+
+#ifndef QAMEL_FONT_H
+#define QAMEL_FONT_H
+
+#ifdef __cplusplus
+extern "C" {
+#endif
+
+char** Font_AddApplicationFont(char* fileName, int* count);
+char** Font_AddApplicationFontFromData(char* data, int length, int* count);
+char** Font_FontFiles(char* dirPath, int* count);
+
+#ifdef __cplusplus
+}
+#endif
+
+#endif
@@ -0,0 +1,379 @@
+package qamel
+
+// #include <stdint.h>
+// #include <stdlib.h>
+// #include <stdbool.h>
+// #include "window.h"
+import "C"
+import (
+	"expvar"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unsafe"
+)
+
+const (
+	// frameHistory is the number of recent frames that used to compute the frame stats.
+	frameHistory = 600
+
+	// defaultRefreshRate is used when the refresh rate of the screen is unknown.
+	defaultRefreshRate = 60
+)
+
+// FrameStats is the rendering performance of a window, computed from the recent frames.
+type FrameStats struct {
+	// FPS is the number of frames per second while the window is rendering continuously,
+	// e.g. while animating. Frames that rendered on demand are not included.
+	FPS float64 `json:"fps"`
+
+	// FrameTime is the average interval between two continuous frames, while
+	// FrameTimeP50, FrameTimeP90, FrameTimeP99 and FrameTimeMax are its percentiles.
+	FrameTime    time.Duration `json:"frameTime"`
+	FrameTimeP50 time.Duration `json:"frameTimeP50"`
+	FrameTimeP90 time.Duration `json:"frameTimeP90"`
+	FrameTimeP99 time.Duration `json:"frameTimeP99"`
+	FrameTimeMax time.Duration `json:"frameTimeMax"`
+
+	// RenderTime is the average time that needed to render the scene graph of a frame.
+	RenderTime time.Duration `json:"renderTime"`
+
+	// TotalFrames is the number of frames since the frame stats enabled.
+	TotalFrames uint64 `json:"totalFrames"`
+
+	// DroppedFrames is the number of frames that missed the refresh of
+	// the screen since the frame stats enabled, i.e. the jank.
+	DroppedFrames uint64 `json:"droppedFrames"`
+}
+
+// frameTracker records the timing of the recent frames of a window.
+type frameTracker struct {
+	sync.Mutex
+	expected  time.Duration
+	lastSwap  time.Duration
+	intervals [frameHistory]time.Duration
+	renders   [frameHistory]time.Duration
+	count     int
+	next      int
+	total     uint64
+	dropped   uint64
+}
+
+var (
+	frameTrackerMutex = sync.RWMutex{}
+	mapFrameTracker   = map[unsafe.Pointer]*frameTracker{}
+)
+
+// newFrameTracker creates frame tracker for a screen with the specified refresh rate.
+func newFrameTracker(refreshRate float64) *frameTracker {
+	if refreshRate <= 0 {
+		refreshRate = defaultRefreshRate
+	}
+
+	return &frameTracker{expected: time.Duration(float64(time.Second) / refreshRate)}
+}
+
+// getFrameTracker returns the frame tracker of the window with the specified pointer and
+// handle ID. If the window is not tracked yet, it will be tracked from now on. The refresh
+// rate is only queried when the tracker created, so the later calls don't need the GUI thread.
+func getFrameTracker(ptr unsafe.Pointer, id uint64, refreshRate func() float64) *frameTracker {
+	if !handleValid(ptr, id) {
+		return nil
+	}
+
+	frameTrackerMutex.RLock()
+	tracker, ok := mapFrameTracker[ptr]
+	frameTrackerMutex.RUnlock()
+
+	if ok {
+		return tracker
+	}
+
+	newTracker := newFrameTracker(refreshRate())
+
+	frameTrackerMutex.Lock()
+	tracker, ok = mapFrameTracker[ptr]
+	if !ok {
+		tracker = newTracker
+		mapFrameTracker[ptr] = tracker
+
+		cleanup := func() {
+			frameTrackerMutex.Lock()
+			delete(mapFrameTracker, ptr)
+			frameTrackerMutex.Unlock()
+		}
+
+		// If the window destroyed in the meantime, the cleanup is never called
+		if _, added := addHandleCleanup(ptr, id, cleanup); !added {
+			delete(mapFrameTracker, ptr)
+			frameTrackerMutex.Unlock()
+			return nil
+		}
+	}
+	frameTrackerMutex.Unlock()
+
+	if !ok && !bool(C.Window_TrackFrames(ptr, C.uint64_t(id))) {
+		return nil
+	}
+
+	return tracker
+}
+
+// record saves the timing of a frame that synchronized and swapped at the specified time.
+func (t *frameTracker) record(syncTime, swapTime, renderTime time.Duration) {
+	t.Lock()
+	defer t.Unlock()
+
+	t.total++
+	lastSwap := t.lastSwap
+	t.lastSwap = swapTime
+
+	// Qt Quick only renders when the scene changed. If the frame synchronized long after the
+	// previous one swapped, the window had nothing to render in between, so the frame is
+	// rendered on demand. It's measured from its synchronization and not included in the
+	// frame time, since the interval from the previous frame is not a rendering delay.
+	if lastSwap == 0 || syncTime-lastSwap > t.expected {
+		t.countDropped(swapTime - syncTime)
+		return
+	}
+
+	interval := swapTime - lastSwap
+	t.intervals[t.next] = interval
+	t.renders[t.next] = renderTime
+	t.next = (t.next + 1) % frameHistory
+	if t.count < frameHistory {
+		t.count++
+	}
+
+	t.countDropped(interval)
+}
+
+// countDropped counts the refreshes of the screen that missed within duration.
+func (t *frameTracker) countDropped(duration time.Duration) {
+	if duration > t.expected*3/2 {
+		t.dropped += uint64((duration+t.expected/2)/t.expected) - 1
+	}
+}
+
+// stats computes the frame stats from the recorded frames.
+func (t *frameTracker) stats() FrameStats {
+	if t == nil {
+		return FrameStats{}
+	}
+
+	t.Lock()
+	intervals := append([]time.Duration{}, t.intervals[:t.count]...)
+	renders := append([]time.Duration{}, t.renders[:t.count]...)
+	stats := FrameStats{TotalFrames: t.total, DroppedFrames: t.dropped}
+	t.Unlock()
+
+	if len(intervals) == 0 {
+		return stats
+	}
+
+	var sumInterval, sumRender time.Duration
+	for i := range intervals {
+		sumInterval += intervals[i]
+		sumRender += renders[i]
+	}
+
+	sort.Slice(intervals, func(i, j int) bool {
+		return intervals[i] < intervals[j]
+	})
+
+	n := len(intervals)
+	stats.FPS = float64(n) / sumInterval.Seconds()
+	stats.FrameTime = sumInterval / time.Duration(n)
+	stats.FrameTimeP50 = percentile(intervals, 50)
+	stats.FrameTimeP90 = percentile(intervals, 90)
+	stats.FrameTimeP99 = percentile(intervals, 99)
+	stats.FrameTimeMax = intervals[n-1]
+	stats.RenderTime = sumRender / time.Duration(n)
+	return stats
+}
+
+// percentile returns the p-th percentile of the sorted values using nearest-rank method.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+
+	return sorted[rank-1]
+}
+
+// watchFrameStats calls callback with the frame stats of tracker every interval,
+// until the returned function is called or the window destroyed.
+func watchFrameStats(ptr unsafe.Pointer, id uint64, tracker *frameTracker,
+	interval time.Duration, callback func(FrameStats)) {
+	if tracker == nil || callback == nil || interval <= 0 {
+		return
+	}
+
+	done := make(chan struct{})
+	var once sync.Once
+	stop := func() { once.Do(func() { close(done) }) }
+	if _, ok := addHandleCleanup(ptr, id, stop); !ok {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				callback(tracker.stats())
+			}
+		}
+	}()
+}
+
+// FrameStats returns the rendering performance of the window. The frames are recorded
+// since the first time FrameStats or OnFrameStats called, so the first call might
+// return empty stats.
+func (view Viewer) FrameStats() FrameStats {
+	if !view.valid() {
+		return FrameStats{}
+	}
+
+	return getFrameTracker(view.ptr, view.id, func() float64 { return view.Screen().RefreshRate }).stats()
+}
+
+// OnFrameStats registers callback that will be called with the frame stats every interval,
+// e.g. to log or export the metrics. The callback is called in its own goroutine, not in
+// the GUI thread, and it's stopped once the window destroyed.
+func (view Viewer) OnFrameStats(interval time.Duration, callback func(stats FrameStats)) {
+	if !view.valid() {
+		return
+	}
+
+	tracker := getFrameTracker(view.ptr, view.id, func() float64 { return view.Screen().RefreshRate })
+	watchFrameStats(view.ptr, view.id, tracker, interval, callback)
+}
+
+// FrameStats returns the rendering performance of the window. The frames are recorded
+// since the first time FrameStats or OnFrameStats called, so the first call might
+// return empty stats.
+func (w QuickWindow) FrameStats() FrameStats {
+	if !w.valid() {
+		return FrameStats{}
+	}
+
+	return getFrameTracker(w.ptr, w.id, func() float64 { return w.Screen().RefreshRate }).stats()
+}
+
+// OnFrameStats registers callback that will be called with the frame stats every interval,
+// e.g. to log or export the metrics. The callback is called in its own goroutine, not in
+// the GUI thread, and it's stopped once the window destroyed.
+func (w QuickWindow) OnFrameStats(interval time.Duration, callback func(stats FrameStats)) {
+	if !w.valid() {
+		return
+	}
+
+	tracker := getFrameTracker(w.ptr, w.id, func() float64 { return w.Screen().RefreshRate })
+	watchFrameStats(w.ptr, w.id, tracker, interval, callback)
+}
+
+// PublishFrameStats publishes the frame stats of the window as expvar with the specified
+// name, so it's served in /debug/vars together with the other variables. Like expvar.Publish,
+// it panics if the name is already used.
+func PublishFrameStats(name string, w Window) {
+	w.FrameStats()
+	expvar.Publish(name, expvar.Func(func() interface{} {
+		return w.FrameStats()
+	}))
+}
+
+// WritePrometheus writes the frame stats of a window in Prometheus text format, using window
+// as the value of "window" label. Each metric may only be written once in the same output,
+// so use WriteFrameStatsPrometheus to write the frame stats of several windows.
+func (stats FrameStats) WritePrometheus(w io.Writer, window string) error {
+	return WriteFrameStatsPrometheus(w, map[string]FrameStats{window: stats})
+}
+
+// WriteFrameStatsPrometheus writes the frame stats of several windows in Prometheus text
+// format. The key of windows is used as the value of "window" label to differentiate them.
+func WriteFrameStatsPrometheus(w io.Writer, windows map[string]FrameStats) error {
+	names := make([]string, 0, len(windows))
+	for name := range windows {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var sb strings.Builder
+	writeMetric := func(name, kind, help string, values func(label string, stats FrameStats) []string) {
+		fmt.Fprintf(&sb, "# HELP %s %s\n", name, help)
+		fmt.Fprintf(&sb, "# TYPE %s %s\n", name, kind)
+		for _, window := range names {
+			label := fmt.Sprintf(`window="%s"`, escapePrometheusLabel(window))
+			for _, value := range values(label, windows[window]) {
+				fmt.Fprintf(&sb, "%s%s\n", name, value)
+			}
+		}
+	}
+
+	writeMetric("qamel_frames_per_second", "gauge",
+		"Number of frames per second while the window is rendering.",
+		func(label string, stats FrameStats) []string {
+			return []string{fmt.Sprintf("{%s} %g", label, stats.FPS)}
+		})
+
+	// The percentiles are computed from the recent frames only, so they are written as
+	// gauge with quantile label, instead of summary which requires the sum and count.
+	writeMetric("qamel_frame_time_seconds", "gauge",
+		"Percentiles of interval between two frames of the window.",
+		func(label string, stats FrameStats) []string {
+			return []string{
+				fmt.Sprintf(`{%s,quantile="0.5"} %g`, label, stats.FrameTimeP50.Seconds()),
+				fmt.Sprintf(`{%s,quantile="0.9"} %g`, label, stats.FrameTimeP90.Seconds()),
+				fmt.Sprintf(`{%s,quantile="0.99"} %g`, label, stats.FrameTimeP99.Seconds()),
+				fmt.Sprintf(`{%s,quantile="1"} %g`, label, stats.FrameTimeMax.Seconds()),
+			}
+		})
+
+	writeMetric("qamel_render_time_seconds", "gauge",
+		"Average time to render the scene graph of a frame.",
+		func(label string, stats FrameStats) []string {
+			return []string{fmt.Sprintf("{%s} %g", label, stats.RenderTime.Seconds())}
+		})
+
+	writeMetric("qamel_frames_total", "counter",
+		"Number of frames rendered by the window.",
+		func(label string, stats FrameStats) []string {
+			return []string{fmt.Sprintf("{%s} %d", label, stats.TotalFrames)}
+		})
+
+	writeMetric("qamel_dropped_frames_total", "counter",
+		"Number of frames that missed the refresh of the screen.",
+		func(label string, stats FrameStats) []string {
+			return []string{fmt.Sprintf("{%s} %d", label, stats.DroppedFrames)}
+		})
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// escapePrometheusLabel escapes the label value for Prometheus text format.
+func escapePrometheusLabel(value string) string {
+	value = strings.Replace(value, `\`, `\\`, -1)
+	value = strings.Replace(value, `"`, `\"`, -1)
+	return strings.Replace(value, "\n", `\n`, -1)
+}
+
+//export qamelWindowFrameSwapped
+func qamelWindowFrameSwapped(ptr unsafe.Pointer, syncTime, swapTime, renderTime C.longlong) {
+	frameTrackerMutex.RLock()
+	tracker := mapFrameTracker[ptr]
+	frameTrackerMutex.RUnlock()
+
+	if tracker != nil {
+		tracker.record(time.Duration(syncTime), time.Duration(swapTime), time.Duration(renderTime))
+	}
+}
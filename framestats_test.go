@@ -0,0 +1,203 @@
+package qamel
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFrameTrackerRecord(t *testing.T) {
+	ms := time.Millisecond
+
+	// Each frame is synchronized after the wait since the previous swap,
+	// then swapped after the duration since its synchronization.
+	type frame struct {
+		wait     time.Duration
+		duration time.Duration
+	}
+
+	continuous := func(n int) []frame {
+		frames := make([]frame, n)
+		for i := range frames {
+			frames[i] = frame{wait: ms, duration: 15 * ms}
+		}
+		return frames
+	}
+
+	tests := []struct {
+		name          string
+		frames        []frame
+		fps           float64
+		frameTimeMax  time.Duration
+		droppedFrames uint64
+	}{{
+		name:   "continuous frames",
+		frames: continuous(10),
+		fps:    62.5,
+	}, {
+		name:          "jank while animating",
+		frames:        append(continuous(5), frame{wait: ms, duration: 49 * ms}),
+		fps:           1 / (0.016*4 + 0.05) * 5,
+		frameTimeMax:  50 * ms,
+		droppedFrames: 2,
+	}, {
+		name:   "frames on demand",
+		frames: []frame{{wait: 100 * ms, duration: 5 * ms}, {wait: 200 * ms, duration: 5 * ms}},
+	}, {
+		name:          "slow frame on demand",
+		frames:        []frame{{wait: 100 * ms, duration: 5 * ms}, {wait: 200 * ms, duration: 40 * ms}},
+		droppedFrames: 1,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tracker := newFrameTracker(60)
+			now := time.Second
+			for _, f := range test.frames {
+				syncTime := now + f.wait
+				now = syncTime + f.duration
+				tracker.record(syncTime, now, 2*ms)
+			}
+
+			stats := tracker.stats()
+			if stats.TotalFrames != uint64(len(test.frames)) {
+				t.Errorf("expected %d total frames, got %d", len(test.frames), stats.TotalFrames)
+			}
+
+			if stats.DroppedFrames != test.droppedFrames {
+				t.Errorf("expected %d dropped frames, got %d", test.droppedFrames, stats.DroppedFrames)
+			}
+
+			if diff := stats.FPS - test.fps; diff > 0.01 || diff < -0.01 {
+				t.Errorf("expected %.2f FPS, got %.2f", test.fps, stats.FPS)
+			}
+
+			if test.frameTimeMax > 0 && stats.FrameTimeMax != test.frameTimeMax {
+				t.Errorf("expected max frame time %v, got %v", test.frameTimeMax, stats.FrameTimeMax)
+			}
+		})
+	}
+}
+
+func TestFrameTrackerHistory(t *testing.T) {
+	tracker := newFrameTracker(60)
+	now := time.Second
+	for i := 0; i < frameHistory+10; i++ {
+		now += 16 * time.Millisecond
+		tracker.record(now-15*time.Millisecond, now, time.Millisecond)
+	}
+
+	stats := tracker.stats()
+	if stats.TotalFrames != frameHistory+10 {
+		t.Errorf("expected %d total frames, got %d", frameHistory+10, stats.TotalFrames)
+	}
+
+	if tracker.count != frameHistory {
+		t.Errorf("expected %d recorded frames, got %d", frameHistory, tracker.count)
+	}
+
+	if stats.FrameTime != 16*time.Millisecond || stats.RenderTime != time.Millisecond {
+		t.Errorf("unexpected frame time %v and render time %v", stats.FrameTime, stats.RenderTime)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	sorted := make([]time.Duration, 100)
+	for i := range sorted {
+		sorted[i] = time.Duration(i + 1)
+	}
+
+	tests := []struct {
+		name     string
+		values   []time.Duration
+		p        int
+		expected time.Duration
+	}{
+		{name: "p50", values: sorted, p: 50, expected: 50},
+		{name: "p90", values: sorted, p: 90, expected: 90},
+		{name: "p99", values: sorted, p: 99, expected: 99},
+		{name: "p0 is the minimum", values: sorted, p: 0, expected: 1},
+		{name: "p100 is the maximum", values: sorted, p: 100, expected: 100},
+		{name: "rank is rounded up", values: []time.Duration{1, 2, 3}, p: 50, expected: 2},
+		{name: "single value", values: []time.Duration{7}, p: 99, expected: 7},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if result := percentile(test.values, test.p); result != test.expected {
+				t.Errorf("expected %v, got %v", test.expected, result)
+			}
+		})
+	}
+}
+
+func TestFrameStatsWritePrometheus(t *testing.T) {
+	stats := FrameStats{
+		FPS:           60,
+		FrameTimeP50:  16 * time.Millisecond,
+		FrameTimeP90:  17 * time.Millisecond,
+		FrameTimeP99:  20 * time.Millisecond,
+		FrameTimeMax:  50 * time.Millisecond,
+		RenderTime:    2 * time.Millisecond,
+		TotalFrames:   100,
+		DroppedFrames: 3,
+	}
+
+	expected := `# HELP qamel_frames_per_second Number of frames per second while the window is rendering.
+# TYPE qamel_frames_per_second gauge
+qamel_frames_per_second{window="main \"1\"\\\n"} 60
+# HELP qamel_frame_time_seconds Percentiles of interval between two frames of the window.
+# TYPE qamel_frame_time_seconds gauge
+qamel_frame_time_seconds{window="main \"1\"\\\n",quantile="0.5"} 0.016
+qamel_frame_time_seconds{window="main \"1\"\\\n",quantile="0.9"} 0.017
+qamel_frame_time_seconds{window="main \"1\"\\\n",quantile="0.99"} 0.02
+qamel_frame_time_seconds{window="main \"1\"\\\n",quantile="1"} 0.05
+# HELP qamel_render_time_seconds Average time to render the scene graph of a frame.
+# TYPE qamel_render_time_seconds gauge
+qamel_render_time_seconds{window="main \"1\"\\\n"} 0.002
+# HELP qamel_frames_total Number of frames rendered by the window.
+# TYPE qamel_frames_total counter
+qamel_frames_total{window="main \"1\"\\\n"} 100
+# HELP qamel_dropped_frames_total Number of frames that missed the refresh of the screen.
+# TYPE qamel_dropped_frames_total counter
+qamel_dropped_frames_total{window="main \"1\"\\\n"} 3
+`
+
+	var buf bytes.Buffer
+	if err := stats.WritePrometheus(&buf, "main \"1\"\\\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	if buf.String() != expected {
+		t.Errorf("unexpected output:\n%s", buf.String())
+	}
+}
+
+func TestWriteFrameStatsPrometheus(t *testing.T) {
+	windows := map[string]FrameStats{
+		"settings": {FPS: 30, TotalFrames: 5},
+		"main":     {FPS: 60, TotalFrames: 10, DroppedFrames: 1},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteFrameStatsPrometheus(&buf, windows); err != nil {
+		t.Fatal(err)
+	}
+
+	output := buf.String()
+	for _, header := range []string{
+		"# TYPE qamel_frames_per_second gauge\n",
+		"# TYPE qamel_frame_time_seconds gauge\n",
+		"# TYPE qamel_frames_total counter\n",
+	} {
+		if n := strings.Count(output, header); n != 1 {
+			t.Errorf("expected header %q once, got %d times", header, n)
+		}
+	}
+
+	expected := "qamel_frames_per_second{window=\"main\"} 60\nqamel_frames_per_second{window=\"settings\"} 30\n"
+	if !strings.Contains(output, expected) {
+		t.Errorf("expected windows sorted by name, got:\n%s", output)
+	}
+}
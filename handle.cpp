@@ -0,0 +1,10 @@
+#include "_cgo_export.h"
+#include "handle.h"
+
+bool qamelHandleLive(void* ptr, uint64_t id) {
+    return qamelIsHandleLive(ptr, id);
+}
+
+uint64_t qamelWindowHandle(void* ptr) {
+    return qamelLookupWindowHandle(ptr);
+}
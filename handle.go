@@ -0,0 +1,190 @@
+package qamel
+
+// #include <stdint.h>
+// #include <stdbool.h>
+import "C"
+import (
+	"errors"
+	"sync"
+	"unsafe"
+)
+
+// ErrDestroyed is returned when method is called on object
+// whose native counterpart has been destroyed.
+var ErrDestroyed = errors.New("qamel: object has been destroyed")
+
+// handleKind is the kind of native object.
+type handleKind int
+
+const (
+	viewerHandle handleKind = iota
+	engineHandle
+	windowHandle
+	settingsHandle
+)
+
+// nativeHandle is the record of a live native object.
+type nativeHandle struct {
+	id       uint64
+	kind     handleKind
+	cleanups []*handleCleanup
+}
+
+// handleCleanup is the function that called when the native object released.
+type handleCleanup struct {
+	fn func()
+}
+
+var (
+	handleMutex  = sync.Mutex{}
+	handleLastID = uint64(0)
+	mapHandle    = map[unsafe.Pointer]*nativeHandle{}
+)
+
+// registerHandle records the native object in ptr as live, and returns
+// the ID that used to identify it. Since pointer could be reused after the
+// native object deleted, the ID must be checked together with the pointer.
+func registerHandle(ptr unsafe.Pointer, kind handleKind) uint64 {
+	if ptr == nil {
+		return 0
+	}
+
+	handleMutex.Lock()
+	defer handleMutex.Unlock()
+
+	handleLastID++
+	mapHandle[ptr] = &nativeHandle{id: handleLastID, kind: kind}
+	return handleLastID
+}
+
+// lookupHandle returns the ID and kind of the live native object in ptr. If the object is
+// not registered yet, it will be registered with the specified kind.
+func lookupHandle(ptr unsafe.Pointer, kind handleKind) (uint64, handleKind) {
+	if ptr == nil {
+		return 0, kind
+	}
+
+	handleMutex.Lock()
+	defer handleMutex.Unlock()
+
+	if handle, ok := mapHandle[ptr]; ok {
+		return handle.id, handle.kind
+	}
+
+	handleLastID++
+	mapHandle[ptr] = &nativeHandle{id: handleLastID, kind: kind}
+	return handleLastID, kind
+}
+
+// lookupHandleKind returns the kind of the native object with the specified
+// pointer and ID. If the object is not live anymore, def is returned.
+func lookupHandleKind(ptr unsafe.Pointer, id uint64, def handleKind) handleKind {
+	handleMutex.Lock()
+	defer handleMutex.Unlock()
+
+	if handle, ok := mapHandle[ptr]; ok && handle.id == id {
+		return handle.kind
+	}
+
+	return def
+}
+
+// handleValid checks if the native object with the specified pointer and ID is still live.
+func handleValid(ptr unsafe.Pointer, id uint64) bool {
+	if ptr == nil {
+		return false
+	}
+
+	handleMutex.Lock()
+	defer handleMutex.Unlock()
+
+	handle, ok := mapHandle[ptr]
+	return ok && handle.id == id
+}
+
+// handleError returns the error for the native object with the
+// specified pointer and ID, or nil if the object is still live.
+func handleError(ptr unsafe.Pointer, id uint64) error {
+	switch {
+	case ptr == nil:
+		return ErrNotInitialized
+	case !handleValid(ptr, id):
+		return ErrDestroyed
+	default:
+		return nil
+	}
+}
+
+// nativeError returns the error for native call on object in ptr, which is only done if
+// the object is still live, as reported by done. It returns nil if the call is done.
+func nativeError(ptr unsafe.Pointer, done C.bool) error {
+	switch {
+	case bool(done):
+		return nil
+	case ptr == nil:
+		return ErrNotInitialized
+	default:
+		return ErrDestroyed
+	}
+}
+
+// addHandleCleanup registers function that will be called when the native object released,
+// and returns the function to unregister it, e.g. when the cleanup is not needed anymore
+// before the object released. It returns false if the native object is not live anymore.
+func addHandleCleanup(ptr unsafe.Pointer, id uint64, cleanup func()) (func(), bool) {
+	handleMutex.Lock()
+	defer handleMutex.Unlock()
+
+	handle, ok := mapHandle[ptr]
+	if !ok || handle.id != id {
+		return nil, false
+	}
+
+	entry := &handleCleanup{fn: cleanup}
+	handle.cleanups = append(handle.cleanups, entry)
+
+	remove := func() {
+		handleMutex.Lock()
+		defer handleMutex.Unlock()
+
+		for i, c := range handle.cleanups {
+			if c == entry {
+				handle.cleanups = append(handle.cleanups[:i], handle.cleanups[i+1:]...)
+				return
+			}
+		}
+	}
+
+	return remove, true
+}
+
+// releaseHandle marks the native object as not live anymore, then calls
+// its cleanup functions. It returns false if the object already released.
+func releaseHandle(ptr unsafe.Pointer, id uint64) bool {
+	handleMutex.Lock()
+	handle, ok := mapHandle[ptr]
+	if !ok || handle.id != id {
+		handleMutex.Unlock()
+		return false
+	}
+
+	delete(mapHandle, ptr)
+	handleMutex.Unlock()
+
+	for _, cleanup := range handle.cleanups {
+		cleanup.fn()
+	}
+
+	return true
+}
+
+//export qamelIsHandleLive
+func qamelIsHandleLive(ptr unsafe.Pointer, id C.uint64_t) C.bool {
+	return C.bool(handleValid(ptr, uint64(id)))
+}
+
+//export qamelLookupWindowHandle
+func qamelLookupWindowHandle(ptr unsafe.Pointer) C.uint64_t {
+	id, _ := lookupHandle(ptr, windowHandle)
+	return C.uint64_t(id)
+}
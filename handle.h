@@ -0,0 +1,41 @@
+#pragma once
+
+#ifndef QAMEL_HANDLE_H
+#define QAMEL_HANDLE_H
+
+#include <stdint.h>
+#include <stdbool.h>
+
+#ifdef __cplusplus
+
+#include <QCoreApplication>
+#include "utils.h"
+
+// qamelHandleLive checks if the native object in ptr with the specified handle ID is still
+// live. It must be called in the GUI thread, where the native objects are deleted.
+bool qamelHandleLive(void* ptr, uint64_t id);
+
+// qamelWindowHandle returns the handle ID of window in ptr, registering it if necessary.
+// It must be called in the GUI thread, after the window is tracked by qamelTrackWindow.
+uint64_t qamelWindowHandle(void* ptr);
+
+// qamelRunOnHandle runs func with the native object in ptr in the GUI thread and waits until
+// it finished, as long as the object with the specified handle ID is still live. The handle is
+// checked in the GUI thread, so the object can't be deleted while func is running. It returns
+// false if the object has been destroyed.
+template <typename T, typename Func>
+inline bool qamelRunOnHandle(void* ptr, uint64_t id, Func func) {
+    bool live = false;
+    qamelRunOnGuiThread(qApp, [&]() {
+        live = qamelHandleLive(ptr, id);
+        if (live) {
+            func(static_cast<T*>(ptr));
+        }
+    });
+
+    return live;
+}
+
+#endif // __cplusplus
+
+#endif // QAMEL_HANDLE_H
@@ -0,0 +1,167 @@
+#include "hotreload.h"
+#include <QQuickItem>
+#include <QQuickWindow>
+#include <QSet>
+#include <QtGlobal>
+
+// collectObjects collects obj and all of its descendants, both the QObject children
+// and the visual children of Qt Quick. The named objects are saved by their path.
+static void collectObjects(QObject *obj, const QString &parentPath,
+        QSet<QObject*> &visited, QHash<QString, QList<QObject*>> &result) {
+    if (obj == nullptr || visited.contains(obj)) {
+        return;
+    }
+
+    visited.insert(obj);
+
+    QString path = parentPath;
+    if (!obj->objectName().isEmpty()) {
+        path += "/" + obj->objectName();
+        result[path].append(obj);
+    }
+
+    for (QObject *child : obj->children()) {
+        collectObjects(child, path, visited, result);
+    }
+
+    if (QQuickItem *item = qobject_cast<QQuickItem*>(obj)) {
+        for (QQuickItem *child : item->childItems()) {
+            collectObjects(child, path, visited, result);
+        }
+    }
+
+    if (QQuickWindow *window = qobject_cast<QQuickWindow*>(obj)) {
+        collectObjects(window->contentItem(), path, visited, result);
+    }
+}
+
+// collectNamedObjects returns the named objects inside roots keyed by their path.
+// Since the path is ambiguous for objects that share it, they are excluded.
+static QHash<QString, QObject*> collectNamedObjects(const QList<QObject*> &roots) {
+    QSet<QObject*> visited;
+    QHash<QString, QList<QObject*>> objects;
+    for (int i = 0; i < roots.size(); i++) {
+        collectObjects(roots.at(i), QString::number(i), visited, objects);
+    }
+
+    QHash<QString, QObject*> result;
+    for (auto it = objects.constBegin(); it != objects.constEnd(); ++it) {
+        if (it.value().size() == 1) {
+            result.insert(it.key(), it.value().first());
+        }
+    }
+
+    return result;
+}
+
+void QamelStateSnapshot::capture(const QList<QObject*> &roots, const QHash<QString, QStringList> &registry) {
+    _values.clear();
+
+    QSet<QObject*> visited;
+    QHash<QString, QList<QObject*>> objects;
+    for (int i = 0; i < roots.size(); i++) {
+        collectObjects(roots.at(i), QString::number(i), visited, objects);
+    }
+
+    for (auto it = objects.constBegin(); it != objects.constEnd(); ++it) {
+        QObject *obj = it.value().first();
+        QString name = obj->objectName();
+
+        QStringList properties = registry.value(name);
+        properties.append(obj->property("qamelPreserve").toStringList());
+        if (properties.isEmpty()) {
+            continue;
+        }
+
+        if (it.value().size() > 1) {
+            qWarning("qamel: can't preserve properties of %s, %d objects share the same path",
+                qPrintable(it.key().section('/', 1)), it.value().size());
+            continue;
+        }
+
+        QVariantMap values;
+        for (const QString &property : properties) {
+            QVariant value = obj->property(property.toUtf8().constData());
+            if (value.isValid()) {
+                values.insert(property, value);
+            }
+        }
+
+        _values.insert(it.key(), values);
+    }
+}
+
+void QamelStateSnapshot::restore(const QList<QObject*> &roots) {
+    if (_values.isEmpty()) {
+        return;
+    }
+
+    QHash<QString, QObject*> objects = collectNamedObjects(roots);
+    for (auto it = objects.constBegin(); it != objects.constEnd(); ++it) {
+        if (!_values.contains(it.key())) {
+            continue;
+        }
+
+        QObject *obj = it.value();
+        QVariantMap values = _values.value(it.key());
+        for (auto value = values.constBegin(); value != values.constEnd(); ++value) {
+            obj->setProperty(value.key().toUtf8().constData(), value.value());
+        }
+    }
+
+    _values.clear();
+}
+
+void qamelTrackNormalGeometry(QWindow *window) {
+    if (window == nullptr || window->property("_qamelNormalTracked").toBool()) {
+        return;
+    }
+
+    auto update = [window]() {
+        if (window->windowStates() == Qt::WindowNoState) {
+            window->setProperty("_qamelNormalGeometry", window->geometry());
+        }
+    };
+
+    window->setProperty("_qamelNormalTracked", true);
+    QObject::connect(window, &QWindow::xChanged, window, update);
+    QObject::connect(window, &QWindow::yChanged, window, update);
+    QObject::connect(window, &QWindow::widthChanged, window, update);
+    QObject::connect(window, &QWindow::heightChanged, window, update);
+    update();
+}
+
+QRect qamelNormalGeometry(QWindow *window) {
+    if (window == nullptr) {
+        return QRect();
+    }
+
+    if (window->windowStates() == Qt::WindowNoState) {
+        return window->geometry();
+    }
+
+    return window->property("_qamelNormalGeometry").toRect();
+}
+
+QamelWindowState QamelWindowState::capture(QWindow *window) {
+    QamelWindowState state;
+    if (window != nullptr) {
+        state.captured = true;
+        state.geometry = qamelNormalGeometry(window);
+        state.states = window->windowStates();
+    }
+
+    return state;
+}
+
+void QamelWindowState::restore(QWindow *window) const {
+    if (window == nullptr || !captured) {
+        return;
+    }
+
+    // Restore the normal geometry first, so it's used when the window is restored
+    if (geometry.isValid()) {
+        window->setGeometry(geometry);
+    }
+    window->setWindowStates(states);
+}
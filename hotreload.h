@@ -0,0 +1,54 @@
+#pragma once
+
+#ifndef QAMEL_HOTRELOAD_H
+#define QAMEL_HOTRELOAD_H
+
+#ifdef __cplusplus
+
+#include <QObject>
+#include <QWindow>
+#include <QString>
+#include <QStringList>
+#include <QVariant>
+#include <QHash>
+#include <QList>
+#include <QRect>
+
+// QamelStateSnapshot saves the value of properties that marked for preservation
+// before the QML is reloaded, then restores them to the new objects afterward.
+// An object is identified by its path, i.e. the objectName of itself and its named
+// ancestors joined by slash, and its properties could be marked either from the
+// registry (keyed by objectName, set from Go) or from QML by declaring property
+// `qamelPreserve` that contains list of property names. Objects that share the
+// same path are ambiguous, so they are skipped with a warning.
+class QamelStateSnapshot {
+public:
+    void capture(const QList<QObject*> &roots, const QHash<QString, QStringList> &registry);
+    void restore(const QList<QObject*> &roots);
+
+private:
+    QHash<QString, QVariantMap> _values;
+};
+
+// qamelTrackNormalGeometry records the geometry of window while it's in normal state, so
+// the normal geometry is still known once it's maximized. It's safe to call it more than once.
+void qamelTrackNormalGeometry(QWindow *window);
+
+// qamelNormalGeometry returns the geometry of window when it's in normal state. If the
+// window is maximized and its normal geometry is not tracked, null rect is returned.
+QRect qamelNormalGeometry(QWindow *window);
+
+// QamelWindowState saves the normal geometry and state of a
+// window, so it could be restored after the window is recreated.
+struct QamelWindowState {
+    bool captured = false;
+    QRect geometry;
+    Qt::WindowStates states;
+
+    static QamelWindowState capture(QWindow *window);
+    void restore(QWindow *window) const;
+};
+
+#endif // __cplusplus
+
+#endif // QAMEL_HOTRELOAD_H
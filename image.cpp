@@ -0,0 +1,31 @@
+#include "image.h"
+#include <stdlib.h>
+#include <string.h>
+#include <QImage>
+
+QamelImage qamelImage(const QImage &image) {
+    QamelImage result;
+    memset(&result, 0, sizeof(result));
+    if (image.isNull()) {
+        return result;
+    }
+
+    QImage rgba = image.convertToFormat(QImage::Format_RGBA8888);
+    int size = rgba.bytesPerLine() * rgba.height();
+
+    result.data = static_cast<unsigned char*>(malloc(size));
+    memcpy(result.data, rgba.constBits(), size);
+    result.width = rgba.width();
+    result.height = rgba.height();
+    result.stride = rgba.bytesPerLine();
+    return result;
+}
+
+QImage qamelQImage(QamelImage image) {
+    if (image.data == nullptr) {
+        return QImage();
+    }
+
+    QImage wrapper(image.data, image.width, image.height, image.stride, QImage::Format_RGBA8888);
+    return wrapper.copy();
+}
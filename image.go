@@ -0,0 +1,50 @@
+package qamel
+
+// #include <stdlib.h>
+// #include "image.h"
+import "C"
+import (
+	"image"
+	"image/draw"
+	"unsafe"
+)
+
+// goImage converts C image into Go image. The data of C image will be freed.
+func goImage(cImage C.QamelImage) *image.NRGBA {
+	if cImage.data == nil {
+		return nil
+	}
+	defer C.free(unsafe.Pointer(cImage.data))
+
+	width, height, stride := int(cImage.width), int(cImage.height), int(cImage.stride)
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	data := C.GoBytes(unsafe.Pointer(cImage.data), C.int(stride*height))
+	for y := 0; y < height; y++ {
+		copy(img.Pix[y*img.Stride:y*img.Stride+width*4], data[y*stride:y*stride+width*4])
+	}
+
+	return img
+}
+
+// cImage converts Go image into C image. The returned function
+// must be called to free the C image once it's not used anymore.
+func cImage(img image.Image) (C.QamelImage, func()) {
+	var cImg C.QamelImage
+	if img == nil || img.Bounds().Empty() {
+		return cImg, func() {}
+	}
+
+	// Convert image to tightly packed NRGBA with origin at (0, 0)
+	bounds := img.Bounds()
+	nrgba, ok := img.(*image.NRGBA)
+	if !ok || bounds.Min != (image.Point{}) || nrgba.Stride != bounds.Dx()*4 {
+		nrgba = image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+		draw.Draw(nrgba, nrgba.Bounds(), img, bounds.Min, draw.Src)
+	}
+
+	cImg.data = (*C.uchar)(C.CBytes(nrgba.Pix))
+	cImg.width = C.int(int32(bounds.Dx()))
+	cImg.height = C.int(int32(bounds.Dy()))
+	cImg.stride = C.int(int32(nrgba.Stride))
+	return cImg, func() { C.free(unsafe.Pointer(cImg.data)) }
+}
@@ -0,0 +1,26 @@
+#pragma once
+
+#ifndef QAMEL_IMAGE_H
+#define QAMEL_IMAGE_H
+
+// QamelImage is an image with non-premultiplied RGBA pixels,
+// 8 bit for each channel. The data is allocated by malloc.
+typedef struct {
+    unsigned char* data;
+    int width, height, stride;
+} QamelImage;
+
+#ifdef __cplusplus
+
+#include <QImage>
+
+// qamelImage converts QImage into QamelImage.
+// If the image is null, the data will be nullptr.
+QamelImage qamelImage(const QImage &image);
+
+// qamelQImage converts QamelImage into QImage that owns a copy of the data.
+QImage qamelQImage(QamelImage image);
+
+#endif // __cplusplus
+
+#endif // QAMEL_IMAGE_H
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/go-qamel/qamel/internal/config"
+	"github.com/go-qamel/qamel/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+func i18nCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "i18n",
+		Short: "Manage translations for QML app",
+		Args:  cobra.NoArgs,
+	}
+
+	cmd.AddCommand(i18nUpdateCmd())
+	return cmd
+}
+
+func i18nUpdateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "update",
+		Short: "Extract translatable strings from QML into res/translations",
+		Args:  cobra.NoArgs,
+		Run:   i18nUpdateHandler,
+	}
+
+	cmd.Flags().StringP("profile", "p", "", "profile that used for finding lupdate")
+	cmd.Flags().StringSliceP("locale", "l", []string{}, "locale of new translation file to create, e.g. id or pt_BR")
+
+	return cmd
+}
+
+func i18nUpdateHandler(cmd *cobra.Command, args []string) {
+	// Read flags
+	profileName, _ := cmd.Flags().GetString("profile")
+	locales, _ := cmd.Flags().GetStringSlice("locale")
+
+	// Load config file
+	fmt.Print("Load config file...")
+
+	profileName = strings.TrimSpace(profileName)
+	if profileName == "" {
+		profileName = "default"
+	}
+
+	profile, err := config.LoadProfile(configPath, profileName)
+	if err != nil {
+		fmt.Println()
+		cRedBold.Println("Failed to load profile file:", err)
+		cRedBold.Println("You might need to run `qamel profile setup` again.")
+		os.Exit(1)
+	}
+	cGreen.Println("done")
+
+	// Get project directory in workdir
+	projectDir, err := os.Getwd()
+	if err != nil {
+		cRedBold.Println("Failed to get current working dir:", err)
+		os.Exit(1)
+	}
+
+	// Update translation files
+	fmt.Print("Updating translation files...")
+	tsFiles, err := generator.UpdateTranslations(profile, projectDir, locales)
+	if err != nil {
+		fmt.Println()
+		cRedBold.Println("Failed to update translation files:", err)
+		os.Exit(1)
+	}
+	cGreen.Println("done")
+
+	for _, tsFile := range tsFiles {
+		fmt.Println(tsFile)
+	}
+}
@@ -32,6 +32,7 @@ func QamelCmd() *cobra.Command {
 	cmd.AddCommand(
 		buildCmd(),
 		dockerCmd(),
+		i18nCmd(),
 		profileCmd(),
 	)
 
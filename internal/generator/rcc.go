@@ -2,6 +2,7 @@ package generator
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
 	"os/exec"
 	fp "path/filepath"
@@ -26,7 +27,8 @@ func CreateRccFile(profile config.Profile, projectDir string) error {
 		return ErrNoResourceDir
 	}
 
-	// Get list of file inside resource dir
+	// Get list of file inside resource dir. Translation sources
+	// are not embedded, since they will be compiled into *.qm.
 	var resFiles []string
 	fp.Walk(resDir, func(path string, info os.FileInfo, err error) error {
 		if info.IsDir() {
@@ -34,11 +36,27 @@ func CreateRccFile(profile config.Profile, projectDir string) error {
 		}
 
 		path, _ = fp.Rel(projectDir, path)
+		if fp.Dir(path) == translationDir && fp.Ext(path) == ".ts" {
+			return nil
+		}
+
 		resFiles = append(resFiles, path)
 		return nil
 	})
 
-	if len(resFiles) == 0 {
+	// Compile translations into temp dir, then embed it as res/translations/*.qm
+	qmDir, err := ioutil.TempDir("", "qamel-qm-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(qmDir)
+
+	qmNames, err := CompileTranslations(profile, projectDir, qmDir)
+	if err != nil {
+		return err
+	}
+
+	if len(resFiles) == 0 && len(qmNames) == 0 {
 		return fmt.Errorf("no resource available")
 	}
 
@@ -51,6 +69,10 @@ func CreateRccFile(profile config.Profile, projectDir string) error {
 	for _, resFile := range resFiles {
 		qrcContent += fmt.Sprintf("<file>%s</file>\n", resFile)
 	}
+	for _, qmName := range qmNames {
+		qrcContent += fmt.Sprintf("<file alias=\"%s\">%s</file>\n",
+			fp.ToSlash(fp.Join(translationDir, qmName)), fp.ToSlash(fp.Join(qmDir, qmName)))
+	}
 	qrcContent += fmt.Sprintln(`</qresource>`)
 	qrcContent += fmt.Sprintln(`</RCC>`)
 
@@ -0,0 +1,129 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	fp "path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/go-qamel/qamel/internal/config"
+)
+
+// translationDir is the directory, relative to project dir,
+// which contains the translation source (*.ts) files.
+var translationDir = fp.Join("res", "translations")
+
+// qtToolPath returns the path to Qt tool with the specified name, e.g. lrelease.
+// The tool is searched in the directory of rcc, then in $PATH.
+func qtToolPath(profile config.Profile, name string) (string, error) {
+	fileName := name
+	if runtime.GOOS == "windows" {
+		fileName += ".exe"
+	}
+
+	toolPath := fp.Join(fp.Dir(profile.Rcc), fileName)
+	if fileExists(toolPath) {
+		return toolPath, nil
+	}
+
+	toolPath, err := exec.LookPath(fileName)
+	if err != nil {
+		return "", fmt.Errorf("%s not found in Qt bin dir or $PATH", name)
+	}
+
+	return toolPath, nil
+}
+
+// translationFiles returns the translation source files inside the project.
+func translationFiles(projectDir string) ([]string, error) {
+	tsFiles, err := fp.Glob(fp.Join(projectDir, translationDir, "*.ts"))
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(tsFiles)
+	return tsFiles, nil
+}
+
+// CompileTranslations compiles the translation files in `projectDir/res/translations`
+// into *.qm files inside dstDir. It returns the name of the compiled files.
+func CompileTranslations(profile config.Profile, projectDir string, dstDir string) ([]string, error) {
+	tsFiles, err := translationFiles(projectDir)
+	if err != nil || len(tsFiles) == 0 {
+		return nil, err
+	}
+
+	lrelease, err := qtToolPath(profile, "lrelease")
+	if err != nil {
+		return nil, err
+	}
+
+	var qmNames []string
+	for _, tsFile := range tsFiles {
+		qmName := strings.TrimSuffix(fp.Base(tsFile), ".ts") + ".qm"
+		cmdLrelease := exec.Command(lrelease, tsFile, "-qm", fp.Join(dstDir, qmName))
+		if btOutput, err := cmdLrelease.CombinedOutput(); err != nil {
+			return nil, fmt.Errorf("failed to compile %s: %v\n%s", fp.Base(tsFile), err, btOutput)
+		}
+
+		qmNames = append(qmNames, qmName)
+	}
+
+	return qmNames, nil
+}
+
+// UpdateTranslations extracts the translatable strings from QML and JS files in
+// `projectDir/res` into the translation files in `projectDir/res/translations`.
+// The file for each of the specified locales (e.g. "id" or "pt_BR") will be
+// created if it doesn't exist yet. It returns the updated translation files.
+func UpdateTranslations(profile config.Profile, projectDir string, locales []string) ([]string, error) {
+	resDir := fp.Join(projectDir, "res")
+	if !dirExists(resDir) {
+		return nil, ErrNoResourceDir
+	}
+
+	tsFiles, err := translationFiles(projectDir)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, locale := range locales {
+		locale = strings.TrimSpace(strings.Replace(locale, "-", "_", -1))
+		if locale == "" {
+			continue
+		}
+
+		tsFile := fp.Join(projectDir, translationDir, locale+".ts")
+		if !fileExists(tsFile) {
+			tsFiles = append(tsFiles, tsFile)
+		}
+	}
+
+	if len(tsFiles) == 0 {
+		return nil, fmt.Errorf("no translation files, specify the locale to create one")
+	}
+
+	err = os.MkdirAll(fp.Join(projectDir, translationDir), os.ModePerm)
+	if err != nil {
+		return nil, err
+	}
+
+	lupdate, err := qtToolPath(profile, "lupdate")
+	if err != nil {
+		return nil, err
+	}
+
+	args := []string{resDir, "-extensions", "qml,js", "-ts"}
+	args = append(args, tsFiles...)
+
+	cmdLupdate := exec.Command(lupdate, args...)
+	if btOutput, err := cmdLupdate.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("%v\n%s", err, btOutput)
+	}
+
+	sort.Strings(tsFiles)
+	return tsFiles, nil
+}
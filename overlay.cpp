@@ -0,0 +1,168 @@
+#include "overlay.h"
+#include <QQmlComponent>
+#include <QQmlContext>
+#include <QByteArray>
+#include <QString>
+#include <QStringList>
+#include <QVariant>
+#include <QUrl>
+#include <QFile>
+#include <QTextStream>
+
+static const char* overlayQml = R"QML(
+import QtQuick 2.0
+
+Rectangle {
+    id: root
+    property var errors: []
+
+    anchors.fill: parent
+    z: 1000000
+    color: "#f0202020"
+
+    MouseArea {
+        anchors.fill: parent
+        acceptedButtons: Qt.AllButtons
+        onWheel: wheel.accepted = false
+    }
+
+    Flickable {
+        anchors.fill: parent
+        anchors.margins: 16
+        contentHeight: content.height
+        clip: true
+
+        Column {
+            id: content
+            width: parent.width
+            spacing: 16
+
+            Text {
+                text: "Failed to load QML"
+                color: "#ff6666"
+                font.pixelSize: 20
+                font.bold: true
+            }
+
+            Repeater {
+                model: root.errors
+
+                Column {
+                    width: content.width
+                    spacing: 4
+
+                    Text {
+                        width: parent.width
+                        text: modelData.location
+                        color: "#ffffff"
+                        font.bold: true
+                        wrapMode: Text.WrapAnywhere
+                    }
+
+                    Text {
+                        width: parent.width
+                        text: modelData.description
+                        color: "#ffaaaa"
+                        wrapMode: Text.Wrap
+                    }
+
+                    Text {
+                        text: modelData.snippet
+                        color: "#cccccc"
+                        font.family: "monospace"
+                        visible: text !== ""
+                    }
+                }
+            }
+        }
+    }
+}
+)QML";
+
+// sourceLine returns the content of the specified line in the source of the URL.
+static QString sourceLine(const QUrl &url, int line) {
+    QString path;
+    if (url.isLocalFile()) {
+        path = url.toLocalFile();
+    } else if (url.scheme() == "qrc") {
+        path = ":" + url.path();
+    } else {
+        return QString();
+    }
+
+    QFile file(path);
+    if (line <= 0 || !file.open(QIODevice::ReadOnly | QIODevice::Text)) {
+        return QString();
+    }
+
+    QTextStream stream(&file);
+    for (int i = 1; !stream.atEnd(); i++) {
+        QString text = stream.readLine();
+        if (i == line) {
+            return text;
+        }
+    }
+
+    return QString();
+}
+
+// errorsToVariant converts the QML errors into list of map, which used as
+// the model of the overlay. Each map contains location, description and snippet.
+static QVariantList errorsToVariant(const QList<QQmlError> &errors) {
+    QVariantList result;
+    for (const QQmlError &error : errors) {
+        QString location = error.url().toString();
+        if (error.line() > 0) {
+            location += QString(":%1").arg(error.line());
+            if (error.column() > 0) {
+                location += QString(":%1").arg(error.column());
+            }
+        }
+
+        QString snippet = sourceLine(error.url(), error.line());
+        if (!snippet.isEmpty() && error.column() > 0) {
+            snippet += "\n" + QString(error.column() - 1, ' ') + "^";
+        }
+
+        QVariantMap item;
+        item["location"] = location;
+        item["description"] = error.description();
+        item["snippet"] = snippet;
+        result.append(item);
+    }
+
+    return result;
+}
+
+QQuickItem* qamelCreateErrorOverlay(QQmlEngine *engine, const QList<QQmlError> &errors, QQuickItem *parent) {
+    QQmlComponent component(engine);
+    component.setData(QByteArray(overlayQml), QUrl("qrc:/qamel/ErrorOverlay.qml"));
+
+    QObject *obj = component.beginCreate(engine->rootContext());
+    QQuickItem *overlay = qobject_cast<QQuickItem*>(obj);
+    if (overlay == nullptr) {
+        delete obj;
+        return nullptr;
+    }
+
+    // QQuickItem doesn't delete its visual children, so the parent must own the overlay
+    overlay->setParent(parent);
+    overlay->setParentItem(parent);
+    overlay->setProperty("errors", errorsToVariant(errors));
+    component.completeCreate();
+    return overlay;
+}
+
+QQuickWindow* qamelCreateErrorWindow(QQmlEngine *engine, const QList<QQmlError> &errors) {
+    QQuickWindow *window = new QQuickWindow();
+    window->setTitle("QML Error");
+    window->resize(640, 480);
+
+    QQuickItem *overlay = qamelCreateErrorOverlay(engine, errors, window->contentItem());
+    if (overlay == nullptr) {
+        delete window;
+        return nullptr;
+    }
+
+    return window;
+}
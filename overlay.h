@@ -0,0 +1,24 @@
+#pragma once
+
+#ifndef QAMEL_OVERLAY_H
+#define QAMEL_OVERLAY_H
+
+#ifdef __cplusplus
+
+#include <QList>
+#include <QQmlEngine>
+#include <QQmlError>
+#include <QQuickItem>
+#include <QQuickWindow>
+
+// qamelCreateErrorOverlay creates an item that shows the list of QML errors,
+// which fills and is owned by the specified parent item. Returns nullptr on failure.
+QQuickItem* qamelCreateErrorOverlay(QQmlEngine *engine, const QList<QQmlError> &errors, QQuickItem *parent);
+
+// qamelCreateErrorWindow creates a window that shows the list of QML errors.
+// It's used when there are no window to put the overlay in.
+QQuickWindow* qamelCreateErrorWindow(QQmlEngine *engine, const QList<QQmlError> &errors);
+
+#endif // __cplusplus
+
+#endif // QAMEL_OVERLAY_H
@@ -0,0 +1,87 @@
+#include "_cgo_export.h"
+#include "palette.h"
+#include "utils.h"
+#include <QGuiApplication>
+#include <QPalette>
+#include <QColor>
+#include <QVariant>
+
+// qamelPlatformPalette is the palette of the platform, i.e. the application palette while
+// there is no custom palette set by Palette_SetPalette. QPalette() can't be used for it,
+// since it's a copy of the current application palette.
+static QPalette *qamelPlatformPalette = nullptr;
+static bool qamelCustomPalette = false;
+
+// qamelTrackPlatformPalette saves the palette of the platform, then refreshes it whenever the
+// application palette changed while there is no custom palette, e.g. when the system theme
+// changed. It must be called in the GUI thread before the first custom palette set.
+static void qamelTrackPlatformPalette() {
+    if (qamelPlatformPalette != nullptr) {
+        return;
+    }
+
+    qamelPlatformPalette = new QPalette(QGuiApplication::palette());
+    QObject::connect(qApp, &QGuiApplication::paletteChanged, qApp, [](const QPalette &palette) {
+        if (!qamelCustomPalette) {
+            *qamelPlatformPalette = palette;
+        }
+    });
+}
+
+void Palette_SetPalette(unsigned int* colors, bool* mask) {
+    qamelRunOnGuiThread(qApp, [&]() {
+        qamelTrackPlatformPalette();
+
+        // Roles that not set will be resolved from the palette of the platform
+        QPalette palette = *qamelPlatformPalette;
+        bool custom = false;
+        for (int group = 0; group < QAMEL_COLOR_GROUPS; group++) {
+            for (int role = 0; role < QAMEL_COLOR_ROLES; role++) {
+                int idx = group * QAMEL_COLOR_ROLES + role;
+                if (mask[idx] && role != QPalette::NoRole) {
+                    palette.setColor(QPalette::ColorGroup(group), QPalette::ColorRole(role),
+                                     QColor::fromRgba(colors[idx]));
+                    custom = true;
+                }
+            }
+        }
+
+        qamelCustomPalette = custom;
+        QGuiApplication::setPalette(palette);
+    });
+}
+
+void Palette_Palette(unsigned int* colors) {
+    QPalette palette;
+    qamelRunOnGuiThread(qApp, [&]() { palette = QGuiApplication::palette(); });
+
+    for (int group = 0; group < QAMEL_COLOR_GROUPS; group++) {
+        for (int role = 0; role < QAMEL_COLOR_ROLES; role++) {
+            int idx = group * QAMEL_COLOR_ROLES + role;
+            if (role == QPalette::NoRole || role >= QPalette::NColorRoles) {
+                colors[idx] = 0;
+            } else {
+                colors[idx] = palette.color(QPalette::ColorGroup(group), QPalette::ColorRole(role)).rgba();
+            }
+        }
+    }
+}
+
+bool Palette_Watch() {
+    if (qApp == nullptr) {
+        return false;
+    }
+
+    qamelRunOnGuiThread(qApp, [&]() {
+        if (qApp->property("_qamelPaletteWatched").toBool()) {
+            return;
+        }
+
+        qApp->setProperty("_qamelPaletteWatched", true);
+        QObject::connect(qApp, &QGuiApplication::paletteChanged, qApp, []() {
+            qamelPaletteChanged();
+        });
+    });
+
+    return true;
+}
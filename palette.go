@@ -0,0 +1,331 @@
+package qamel
+
+// #include <stdlib.h>
+// #include <stdbool.h>
+// #include "palette.h"
+import "C"
+import (
+	"image/color"
+	"sync"
+)
+
+const (
+	nColorGroups = int(C.QAMEL_COLOR_GROUPS)
+	nColorRoles  = int(C.QAMEL_COLOR_ROLES)
+)
+
+// ColorGroup is the group of colors in palette, which depends on the state of widget.
+type ColorGroup int32
+
+const (
+	// ColorGroupActive is the group for the window that has keyboard focus.
+	ColorGroupActive ColorGroup = 0
+	// ColorGroupDisabled is the group for the disabled items.
+	ColorGroupDisabled = 1
+	// ColorGroupInactive is the group for the other windows.
+	ColorGroupInactive = 2
+)
+
+// ColorRole is the role of color in palette.
+type ColorRole int32
+
+const (
+	// WindowTextRole is a general foreground color.
+	WindowTextRole ColorRole = 0
+	// ButtonRole is the general button background color.
+	ButtonRole = 1
+	// LightRole is lighter than Button color.
+	LightRole = 2
+	// MidlightRole is between Button and Light.
+	MidlightRole = 3
+	// DarkRole is darker than Button.
+	DarkRole = 4
+	// MidRole is between Button and Dark.
+	MidRole = 5
+	// TextRole is the foreground color used with Base. This is usually
+	// the same as the WindowText, in which case it must provide good
+	// contrast with Window and Base.
+	TextRole = 6
+	// BrightTextRole is a text color that is very different from WindowText,
+	// and contrasts well with e.g. Dark. Typically used for text that needs
+	// to be drawn where Text or WindowText would give poor contrast.
+	BrightTextRole = 7
+	// ButtonTextRole is a foreground color used with the Button color.
+	ButtonTextRole = 8
+	// BaseRole is used mostly as the background color for text entry widgets,
+	// but can also be used for other painting - such as the background of
+	// combobox drop down lists and toolbar handles. It is usually white or
+	// another light color.
+	BaseRole = 9
+	// WindowRole is a general background color.
+	WindowRole = 10
+	// ShadowRole is a very dark color. By default, the shadow color is black.
+	ShadowRole = 11
+	// HighlightRole is a color to indicate a selected item or the current item.
+	HighlightRole = 12
+	// HighlightedTextRole is a text color that contrasts with Highlight.
+	HighlightedTextRole = 13
+	// LinkRole is a text color used for unvisited hyperlinks.
+	LinkRole = 14
+	// LinkVisitedRole is a text color used for already visited hyperlinks.
+	LinkVisitedRole = 15
+	// AlternateBaseRole is used as the alternate background color in views with
+	// alternating row colors.
+	AlternateBaseRole = 16
+	// noRole is the role that not used for any color.
+	noRole = 17
+	// ToolTipBaseRole is used as the background color for tooltips.
+	ToolTipBaseRole = 18
+	// ToolTipTextRole is used as the foreground color for tooltips.
+	ToolTipTextRole = 19
+	// PlaceholderTextRole is used as the placeholder color for various text
+	// input widgets. This value was added in Qt 5.12.
+	PlaceholderTextRole = 20
+)
+
+// ColorScheme is the color scheme of the application, i.e. light or dark.
+type ColorScheme int32
+
+const (
+	// ColorSchemeUnknown is used when the color scheme is not known. When it's used
+	// in Application.SetColorScheme, the application will follow the system theme.
+	ColorSchemeUnknown ColorScheme = 0
+	// ColorSchemeLight is the color scheme with dark text on light background.
+	ColorSchemeLight = 1
+	// ColorSchemeDark is the color scheme with light text on dark background.
+	ColorSchemeDark = 2
+)
+
+var (
+	paletteMutex        = sync.RWMutex{}
+	colorSchemeCallback func(scheme ColorScheme)
+	lastColorScheme     ColorScheme
+)
+
+// Palette is the color palette of the application, which contains color
+// for each role in each group. The zero value is an empty palette, where
+// every color will be resolved from the default palette of the platform.
+type Palette struct {
+	colors [nColorGroups * nColorRoles]color.NRGBA
+	mask   [nColorGroups * nColorRoles]bool
+}
+
+// SetColor sets the color that used for the specified group and role.
+func (p *Palette) SetColor(group ColorGroup, role ColorRole, c color.Color) {
+	if group < 0 || int(group) >= nColorGroups || role < 0 || int(role) >= nColorRoles {
+		return
+	}
+
+	idx := int(group)*nColorRoles + int(role)
+	p.colors[idx] = color.NRGBAModel.Convert(c).(color.NRGBA)
+	p.mask[idx] = true
+}
+
+// SetRoleColor sets the color that used for the specified role in all groups.
+func (p *Palette) SetRoleColor(role ColorRole, c color.Color) {
+	for group := 0; group < nColorGroups; group++ {
+		p.SetColor(ColorGroup(group), role, c)
+	}
+}
+
+// Color returns the color that used for the specified group and role.
+func (p Palette) Color(group ColorGroup, role ColorRole) color.NRGBA {
+	if group < 0 || int(group) >= nColorGroups || role < 0 || int(role) >= nColorRoles {
+		return color.NRGBA{}
+	}
+
+	return p.colors[int(group)*nColorRoles+int(role)]
+}
+
+// IsSet checks if the color for the specified group and role has been set.
+func (p Palette) IsSet(group ColorGroup, role ColorRole) bool {
+	if group < 0 || int(group) >= nColorGroups || role < 0 || int(role) >= nColorRoles {
+		return false
+	}
+
+	return p.mask[int(group)*nColorRoles+int(role)]
+}
+
+// colorScheme detects the color scheme of the palette by
+// comparing the lightness of the window and its text.
+func (p Palette) colorScheme() ColorScheme {
+	window := p.Color(ColorGroupActive, WindowRole)
+	text := p.Color(ColorGroupActive, WindowTextRole)
+	if window.A == 0 && text.A == 0 {
+		return ColorSchemeUnknown
+	}
+
+	if luminance(window) < luminance(text) {
+		return ColorSchemeDark
+	}
+
+	return ColorSchemeLight
+}
+
+// luminance returns the perceived brightness of the color, between 0 and 255.
+func luminance(c color.NRGBA) float64 {
+	return 0.299*float64(c.R) + 0.587*float64(c.G) + 0.114*float64(c.B)
+}
+
+// SetPalette changes the default application palette to p. The colors that not set in p are
+// resolved from the palette of the platform, which follows the system theme while there is no
+// custom palette, so use an empty Palette to restore the system palette.
+func (app Application) SetPalette(p Palette) {
+	var cColors [nColorGroups * nColorRoles]C.uint
+	var cMask [nColorGroups * nColorRoles]C.bool
+	for i, c := range p.colors {
+		cColors[i] = C.uint(uint32(c.A)<<24 | uint32(c.R)<<16 | uint32(c.G)<<8 | uint32(c.B))
+		cMask[i] = C.bool(p.mask[i])
+	}
+
+	C.Palette_SetPalette(&cColors[0], &cMask[0])
+}
+
+// Palette returns the current application palette.
+func (app Application) Palette() Palette {
+	var cColors [nColorGroups * nColorRoles]C.uint
+	C.Palette_Palette(&cColors[0])
+
+	var p Palette
+	for i, c := range cColors {
+		argb := uint32(c)
+		p.colors[i] = color.NRGBA{
+			R: uint8(argb >> 16),
+			G: uint8(argb >> 8),
+			B: uint8(argb),
+			A: uint8(argb >> 24),
+		}
+		p.mask[i] = i%nColorRoles != noRole
+	}
+
+	return p
+}
+
+// ColorScheme returns the color scheme of the application, which detected from its palette.
+// If the palette is not changed manually, it's the color scheme of the system theme.
+func (app Application) ColorScheme() ColorScheme {
+	return app.Palette().colorScheme()
+}
+
+// SetColorScheme switches the application to light or dark palette. Use ColorSchemeUnknown
+// to restore the palette of the system theme. It also sets the theme of Material and Universal
+// styles of Qt Quick Controls 2, both as the default for QML that loaded later and for the
+// existing windows that import the style. For Viewer, the theme is set on its root item.
+func (app Application) SetColorScheme(scheme ColorScheme) error {
+	theme := "System"
+	switch scheme {
+	case ColorSchemeDark:
+		app.SetPalette(darkPalette())
+		theme = "Dark"
+	case ColorSchemeLight:
+		app.SetPalette(lightPalette())
+		theme = "Light"
+	default:
+		app.SetPalette(Palette{})
+	}
+
+	if err := SetMaterialTheme(MaterialTheme{Theme: theme}); err != nil {
+		return err
+	}
+
+	return SetUniversalTheme(UniversalTheme{Theme: theme})
+}
+
+// OnColorSchemeChanged registers callback that will be called when the color scheme of the
+// application changed, either by the system theme (on platforms that report it) or by
+// SetColorScheme and SetPalette. The callback is called in the GUI thread. Pass nil to
+// remove the callback. It returns ErrNotInitialized if the application is not constructed yet.
+func (app Application) OnColorSchemeChanged(callback func(scheme ColorScheme)) error {
+	if callback != nil && !bool(C.Palette_Watch()) {
+		return ErrNotInitialized
+	}
+
+	scheme := app.ColorScheme()
+
+	paletteMutex.Lock()
+	colorSchemeCallback = callback
+	lastColorScheme = scheme
+	paletteMutex.Unlock()
+	return nil
+}
+
+//export qamelPaletteChanged
+func qamelPaletteChanged() {
+	scheme := Application{}.ColorScheme()
+
+	paletteMutex.Lock()
+	callback := colorSchemeCallback
+	changed := scheme != lastColorScheme
+	lastColorScheme = scheme
+	paletteMutex.Unlock()
+
+	if callback != nil && changed {
+		callback(scheme)
+	}
+}
+
+// darkPalette returns the palette for dark color scheme.
+func darkPalette() Palette {
+	var p Palette
+	p.SetRoleColor(WindowRole, color.NRGBA{53, 53, 53, 255})
+	p.SetRoleColor(WindowTextRole, color.NRGBA{255, 255, 255, 255})
+	p.SetRoleColor(BaseRole, color.NRGBA{42, 42, 42, 255})
+	p.SetRoleColor(AlternateBaseRole, color.NRGBA{66, 66, 66, 255})
+	p.SetRoleColor(ToolTipBaseRole, color.NRGBA{255, 255, 255, 255})
+	p.SetRoleColor(ToolTipTextRole, color.NRGBA{53, 53, 53, 255})
+	p.SetRoleColor(TextRole, color.NRGBA{255, 255, 255, 255})
+	p.SetRoleColor(ButtonRole, color.NRGBA{53, 53, 53, 255})
+	p.SetRoleColor(ButtonTextRole, color.NRGBA{255, 255, 255, 255})
+	p.SetRoleColor(BrightTextRole, color.NRGBA{255, 0, 0, 255})
+	p.SetRoleColor(LightRole, color.NRGBA{85, 85, 85, 255})
+	p.SetRoleColor(MidlightRole, color.NRGBA{70, 70, 70, 255})
+	p.SetRoleColor(MidRole, color.NRGBA{45, 45, 45, 255})
+	p.SetRoleColor(DarkRole, color.NRGBA{35, 35, 35, 255})
+	p.SetRoleColor(ShadowRole, color.NRGBA{20, 20, 20, 255})
+	p.SetRoleColor(HighlightRole, color.NRGBA{42, 130, 218, 255})
+	p.SetRoleColor(HighlightedTextRole, color.NRGBA{255, 255, 255, 255})
+	p.SetRoleColor(LinkRole, color.NRGBA{42, 130, 218, 255})
+	p.SetRoleColor(LinkVisitedRole, color.NRGBA{160, 110, 220, 255})
+	p.SetRoleColor(PlaceholderTextRole, color.NRGBA{127, 127, 127, 255})
+
+	disabled := color.NRGBA{127, 127, 127, 255}
+	p.SetColor(ColorGroupDisabled, WindowTextRole, disabled)
+	p.SetColor(ColorGroupDisabled, TextRole, disabled)
+	p.SetColor(ColorGroupDisabled, ButtonTextRole, disabled)
+	p.SetColor(ColorGroupDisabled, HighlightRole, color.NRGBA{80, 80, 80, 255})
+	p.SetColor(ColorGroupDisabled, HighlightedTextRole, disabled)
+	return p
+}
+
+// lightPalette returns the palette for light color scheme.
+func lightPalette() Palette {
+	var p Palette
+	p.SetRoleColor(WindowRole, color.NRGBA{239, 239, 239, 255})
+	p.SetRoleColor(WindowTextRole, color.NRGBA{0, 0, 0, 255})
+	p.SetRoleColor(BaseRole, color.NRGBA{255, 255, 255, 255})
+	p.SetRoleColor(AlternateBaseRole, color.NRGBA{247, 247, 247, 255})
+	p.SetRoleColor(ToolTipBaseRole, color.NRGBA{255, 255, 220, 255})
+	p.SetRoleColor(ToolTipTextRole, color.NRGBA{0, 0, 0, 255})
+	p.SetRoleColor(TextRole, color.NRGBA{0, 0, 0, 255})
+	p.SetRoleColor(ButtonRole, color.NRGBA{239, 239, 239, 255})
+	p.SetRoleColor(ButtonTextRole, color.NRGBA{0, 0, 0, 255})
+	p.SetRoleColor(BrightTextRole, color.NRGBA{255, 255, 255, 255})
+	p.SetRoleColor(LightRole, color.NRGBA{255, 255, 255, 255})
+	p.SetRoleColor(MidlightRole, color.NRGBA{202, 202, 202, 255})
+	p.SetRoleColor(MidRole, color.NRGBA{184, 184, 184, 255})
+	p.SetRoleColor(DarkRole, color.NRGBA{159, 159, 159, 255})
+	p.SetRoleColor(ShadowRole, color.NRGBA{118, 118, 118, 255})
+	p.SetRoleColor(HighlightRole, color.NRGBA{48, 140, 198, 255})
+	p.SetRoleColor(HighlightedTextRole, color.NRGBA{255, 255, 255, 255})
+	p.SetRoleColor(LinkRole, color.NRGBA{0, 0, 255, 255})
+	p.SetRoleColor(LinkVisitedRole, color.NRGBA{255, 0, 255, 255})
+	p.SetRoleColor(PlaceholderTextRole, color.NRGBA{0, 0, 0, 128})
+
+	disabled := color.NRGBA{190, 190, 190, 255}
+	p.SetColor(ColorGroupDisabled, WindowTextRole, disabled)
+	p.SetColor(ColorGroupDisabled, TextRole, disabled)
+	p.SetColor(ColorGroupDisabled, ButtonTextRole, disabled)
+	p.SetColor(ColorGroupDisabled, HighlightRole, color.NRGBA{145, 145, 145, 255})
+	p.SetColor(ColorGroupDisabled, HighlightedTextRole, color.NRGBA{255, 255, 255, 255})
+	return p
+}
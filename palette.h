@@ -0,0 +1,25 @@
+#pragma once
+
+#ifndef QAMEL_PALETTE_H
+#define QAMEL_PALETTE_H
+
+#include <stdbool.h>
+
+// QAMEL_COLOR_GROUPS and QAMEL_COLOR_ROLES are the number of color groups and
+// color roles in palette. Colors are stored as ARGB in row of groups.
+#define QAMEL_COLOR_GROUPS 3
+#define QAMEL_COLOR_ROLES 21
+
+#ifdef __cplusplus
+extern "C" {
+#endif
+
+void Palette_SetPalette(unsigned int* colors, bool* mask);
+void Palette_Palette(unsigned int* colors);
+bool Palette_Watch();
+
+#ifdef __cplusplus
+}
+#endif
+
+#endif
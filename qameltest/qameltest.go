@@ -0,0 +1,261 @@
+// Package qameltest provides utilities for testing QML UI from `go test`.
+//
+// The tests run with a Qt application which uses the offscreen platform, so they
+// don't need any display. Since Qt requires its GUI to live in the main thread,
+// the test binary must hand its main thread to the application by calling Main
+// from TestMain:
+//
+//	func TestMain(m *testing.M) {
+//		qameltest.Main(m, func(app *qamel.Application) {
+//			RegisterQmlBackEnd("BackEnd", 1, 0, "BackEnd")
+//		})
+//	}
+//
+//	func TestCounter(t *testing.T) {
+//		w := qameltest.Load(t, "qrc:/res/main.qml")
+//		defer w.Close()
+//
+//		w.Click("incrementButton")
+//		w.WaitForProperty("counterLabel", "text", "1", time.Second)
+//	}
+//
+// Like any app that uses qamel, the test binary must be built with the cgo
+// flags that generated by `qamel build`.
+package qameltest
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-qamel/qamel"
+)
+
+// pollInterval is the interval for checking condition in WaitFor.
+const pollInterval = 10 * time.Millisecond
+
+// Main creates the Qt application on offscreen platform, runs the tests in m then
+// exits with the result of the tests. The setup function, if not nil, is called in
+// the GUI thread before the tests run, e.g. to register QML types for test backends.
+// Main must be called from TestMain since it needs the main thread.
+func Main(m *testing.M, setup func(app *qamel.Application)) {
+	if os.Getenv("QT_QPA_PLATFORM") == "" {
+		os.Setenv("QT_QPA_PLATFORM", "offscreen")
+	}
+
+	app := qamel.NewApplication(len(os.Args), os.Args)
+	app.SetQuitOnLastWindowClosed(false)
+	if setup != nil {
+		setup(app)
+	}
+
+	exitCode := make(chan int, 1)
+	go func() {
+		exitCode <- m.Run()
+		app.Quit()
+	}()
+
+	app.Exec()
+	os.Exit(<-exitCode)
+}
+
+// Window is a QML window that loaded for testing.
+type Window struct {
+	t    testing.TB
+	view qamel.Viewer
+}
+
+// Load creates a window and loads the QML from source, which could be a local file path
+// or a Qt resource URL. The test fails immediately if the QML can't be loaded.
+func Load(t testing.TB, source string) *Window {
+	t.Helper()
+
+	w := newWindow(t)
+	qamel.RunOnGuiThread(func() {
+		w.view.SetSource(source)
+		w.view.Show()
+	})
+
+	if err := w.view.Errors(); err != nil {
+		w.Close()
+		t.Fatalf("failed to load %s: %v", source, err)
+	}
+
+	return w
+}
+
+// LoadData creates a window and loads the QML from data. The baseURL is used to resolve
+// relative imports and URLs. The test fails immediately if the QML can't be loaded.
+func LoadData(t testing.TB, data []byte, baseURL string) *Window {
+	t.Helper()
+
+	w := newWindow(t)
+	if err := w.view.SetSourceData(data, baseURL); err != nil {
+		w.Close()
+		t.Fatalf("failed to load QML data: %v", err)
+	}
+
+	qamel.RunOnGuiThread(func() { w.view.Show() })
+	return w
+}
+
+// newWindow creates a new window in the GUI thread.
+func newWindow(t testing.TB) *Window {
+	w := &Window{t: t}
+	qamel.RunOnGuiThread(func() {
+		w.view = qamel.NewViewer()
+		w.view.SetResizeMode(qamel.SizeRootObjectToView)
+		w.view.SetWidth(800)
+		w.view.SetHeight(600)
+	})
+
+	return w
+}
+
+// Viewer returns the underlying viewer of the window.
+func (w *Window) Viewer() qamel.Viewer {
+	return w.view
+}
+
+// Close closes the window and destroys it.
+func (w *Window) Close() {
+	w.view.Close()
+}
+
+// Property returns the value of property of the item with the specified objectName.
+// The value is converted through JSON, so numbers are returned as float64.
+// The test fails immediately if the item or property doesn't exist.
+func (w *Window) Property(objectName string, property string) interface{} {
+	w.t.Helper()
+
+	value, err := w.view.ItemProperty(objectName, property)
+	if err != nil {
+		w.t.Fatalf("failed to get property: %v", err)
+	}
+
+	return value
+}
+
+// SetProperty sets the value of property of the item with the specified objectName.
+// The test fails immediately if the property can't be set.
+func (w *Window) SetProperty(objectName string, property string, value interface{}) {
+	w.t.Helper()
+
+	if err := w.view.SetItemProperty(objectName, property, value); err != nil {
+		w.t.Fatalf("failed to set property: %v", err)
+	}
+}
+
+// Click sends left mouse click to the center of the item with the specified objectName.
+// The test fails immediately if the item doesn't exist.
+func (w *Window) Click(objectName string) {
+	w.t.Helper()
+
+	if err := w.view.ClickItem(objectName); err != nil {
+		w.t.Fatalf("failed to click: %v", err)
+	}
+}
+
+// Focus gives the active focus to the item with the specified objectName.
+// The test fails immediately if the item doesn't exist.
+func (w *Window) Focus(objectName string) {
+	w.t.Helper()
+
+	if err := w.view.FocusItem(objectName); err != nil {
+		w.t.Fatalf("failed to focus: %v", err)
+	}
+}
+
+// KeyPress sends key press and release to the item that has active focus.
+// The test fails immediately if the key can't be sent.
+func (w *Window) KeyPress(key qamel.Key, modifiers qamel.KeyboardModifiers) {
+	w.t.Helper()
+
+	if err := w.view.SendKey(key, modifiers, ""); err != nil {
+		w.t.Fatalf("failed to press key: %v", err)
+	}
+}
+
+// TypeText sends key events for each character in text to the item that has
+// active focus, as if the text is typed by user. The test fails immediately
+// if the text can't be sent.
+func (w *Window) TypeText(text string) {
+	w.t.Helper()
+
+	if err := w.view.SendText(text); err != nil {
+		w.t.Fatalf("failed to type text: %v", err)
+	}
+}
+
+// WaitFor calls condition repeatedly until it returns true or timeout is reached.
+// It returns true if the condition is satisfied before timeout.
+func (w *Window) WaitFor(timeout time.Duration, condition func() bool) bool {
+	deadline := time.Now().Add(timeout)
+	for {
+		if condition() {
+			return true
+		}
+
+		if time.Now().After(deadline) {
+			return false
+		}
+
+		time.Sleep(pollInterval)
+	}
+}
+
+// WaitForProperty waits until the property of the item with the specified objectName
+// equals to expected. The test fails if it doesn't happen until timeout.
+func (w *Window) WaitForProperty(objectName string, property string, expected interface{}, timeout time.Duration) {
+	w.t.Helper()
+
+	var value interface{}
+	ok := w.WaitFor(timeout, func() bool {
+		var err error
+		value, err = w.view.ItemProperty(objectName, property)
+		return err == nil && equal(value, expected)
+	})
+
+	if !ok {
+		w.t.Fatalf("%s.%s is %v after %v, want %v", objectName, property, value, timeout, expected)
+	}
+}
+
+// AssertProperty checks if the property of the item with the specified objectName
+// equals to expected. The values are compared after converted through JSON, so
+// e.g. int 1 is equal to float64 1. The test is marked as failed if they differ.
+func (w *Window) AssertProperty(objectName string, property string, expected interface{}) bool {
+	w.t.Helper()
+
+	value, err := w.view.ItemProperty(objectName, property)
+	if err != nil {
+		w.t.Errorf("failed to get property: %v", err)
+		return false
+	}
+
+	if !equal(value, expected) {
+		w.t.Errorf("%s.%s is %v, want %v", objectName, property, value, expected)
+		return false
+	}
+
+	return true
+}
+
+// equal checks if value from QML equals to expected value
+// after the expected value converted through JSON.
+func equal(value interface{}, expected interface{}) bool {
+	bt, err := json.Marshal(expected)
+	if err != nil {
+		panic(fmt.Sprintf("qameltest: can't compare with %v: %v", expected, err))
+	}
+
+	var normalized interface{}
+	if err = json.Unmarshal(bt, &normalized); err != nil {
+		return false
+	}
+
+	return reflect.DeepEqual(value, normalized)
+}
@@ -0,0 +1,92 @@
+package qameltest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-qamel/qamel"
+)
+
+const counterQML = `
+import QtQuick 2.12
+
+Rectangle {
+	width: 400
+	height: 300
+
+	Text {
+		id: counterLabel
+		objectName: "counterLabel"
+		property int count: 0
+		text: count
+	}
+
+	MouseArea {
+		objectName: "incrementButton"
+		width: 100
+		height: 50
+		y: 100
+		onClicked: counterLabel.count++
+	}
+
+	TextInput {
+		objectName: "nameInput"
+		width: 200
+		height: 30
+		y: 200
+	}
+}
+`
+
+func TestMain(m *testing.M) {
+	Main(m, nil)
+}
+
+func TestClick(t *testing.T) {
+	w := LoadData(t, []byte(counterQML), "qrc:/qameltest/Counter.qml")
+	defer w.Close()
+
+	w.AssertProperty("counterLabel", "text", "0")
+	w.Click("incrementButton")
+	w.Click("incrementButton")
+	w.WaitForProperty("counterLabel", "text", "2", time.Second)
+}
+
+func TestSetProperty(t *testing.T) {
+	w := LoadData(t, []byte(counterQML), "qrc:/qameltest/Counter.qml")
+	defer w.Close()
+
+	w.SetProperty("counterLabel", "count", 41)
+	w.AssertProperty("counterLabel", "count", 41)
+	w.AssertProperty("counterLabel", "text", "41")
+}
+
+func TestTypeText(t *testing.T) {
+	w := LoadData(t, []byte(counterQML), "qrc:/qameltest/Counter.qml")
+	defer w.Close()
+
+	w.Focus("nameInput")
+	w.TypeText("qamel")
+	w.KeyPress(qamel.KeyBackspace, qamel.NoModifier)
+	w.WaitForProperty("nameInput", "text", "qame", time.Second)
+}
+
+func TestEqual(t *testing.T) {
+	tests := []struct {
+		value    interface{}
+		expected interface{}
+		equal    bool
+	}{
+		{value: float64(1), expected: 1, equal: true},
+		{value: "1", expected: 1, equal: false},
+		{value: []interface{}{"a", float64(2)}, expected: []interface{}{"a", 2}, equal: true},
+		{value: map[string]interface{}{"x": true}, expected: map[string]bool{"x": true}, equal: true},
+		{value: nil, expected: nil, equal: true},
+	}
+
+	for _, test := range tests {
+		if result := equal(test.value, test.expected); result != test.equal {
+			t.Errorf("equal(%v, %v): expected %v, got %v", test.value, test.expected, test.equal, result)
+		}
+	}
+}
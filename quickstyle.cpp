@@ -1,6 +1,14 @@
 #include "quickstyle.h"
+#include "utils.h"
 #include <QQuickStyle>
 #include <QString>
+#include <QGuiApplication>
+#include <QQuickWindow>
+#include <QQuickView>
+#include <QStringList>
+#include <QQmlContext>
+#include <QQmlEngine>
+#include <QQmlExpression>
 
 void SetQuickStyle(char* style) {
     QQuickStyle::setStyle(QString(style));
@@ -13,3 +21,46 @@ void SetQuickStyleFallback(char* style) {
 void AddQuickStylePath(char* style) {
     QQuickStyle::addStylePath(QString(style));
 }
+
+char** AvailableQuickStyles(int* count) {
+    return qamelCStringArray(QQuickStyle::availableStyles(), count);
+}
+
+char* QuickStyleName() {
+    return qamelCString(QQuickStyle::name());
+}
+
+char* ApplyQuickStyleAttributes(char* script) {
+    if (qApp == nullptr) {
+        return nullptr;
+    }
+
+    QString expression(script);
+    QStringList errors;
+    qamelRunOnGuiThread(qApp, [&]() {
+        for (QWindow *window : QGuiApplication::topLevelWindows()) {
+            // QQuickView is not created from QML, so the style is attached to its root item
+            QObject *target = window;
+            if (QQuickView *view = qobject_cast<QQuickView*>(window)) {
+                target = view->rootObject();
+            }
+
+            QQmlContext *context = target != nullptr ? qmlContext(target) : nullptr;
+            if (context == nullptr) {
+                continue;
+            }
+
+            QQmlExpression qmlExpression(context, target, expression);
+            qmlExpression.evaluate();
+            if (qmlExpression.hasError()) {
+                errors.append(qmlExpression.error().toString());
+            }
+        }
+    });
+
+    if (errors.isEmpty()) {
+        return nullptr;
+    }
+
+    return qamelCString(errors.join('\n'));
+}
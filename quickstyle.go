@@ -3,12 +3,54 @@ package qamel
 // #include <stdlib.h>
 // #include "quickstyle.h"
 import "C"
-import "unsafe"
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"unsafe"
+)
+
+var (
+	rxStyleColorName = regexp.MustCompile(`^[A-Za-z]+$`)
+	rxStyleColorHex  = regexp.MustCompile(`^#([0-9A-Fa-f]{3}|[0-9A-Fa-f]{6}|[0-9A-Fa-f]{8})$`)
+)
+
+// MaterialTheme is the configuration for Material style of Qt Quick Controls 2,
+// equivalent with the [Material] section in qtquickcontrols2.conf. Empty field
+// means the value is not changed.
+type MaterialTheme struct {
+	// Theme is either "Light", "Dark" or "System".
+	Theme string
+
+	// Accent, Primary, Foreground and Background is either the name of
+	// predefined Material color (e.g. "Teal") or hex color (e.g. "#41cd52").
+	Accent     string
+	Primary    string
+	Foreground string
+	Background string
+}
+
+// UniversalTheme is the configuration for Universal style of Qt Quick Controls 2,
+// equivalent with the [Universal] section in qtquickcontrols2.conf. Empty field
+// means the value is not changed.
+type UniversalTheme struct {
+	// Theme is either "Light", "Dark" or "System".
+	Theme string
+
+	// Accent, Foreground and Background is either the name of predefined
+	// Universal color (e.g. "Cobalt") or hex color (e.g. "#41cd52").
+	Accent     string
+	Foreground string
+	Background string
+}
 
 // SetQuickStyle sets the application style to style.
 // Note that the style must be configured before loading QML that
 // imports Qt Quick Controls 2. It is not possible to change
-// the style after the QML types have been registered.
+// the style after the QML types have been registered. The style
+// could be a name of available style or a path to custom style.
+// Use ValidateQuickStyle to check if the style is available.
 func SetQuickStyle(style string) {
 	cStyle := C.CString(style)
 	defer C.free(unsafe.Pointer(cStyle))
@@ -32,3 +74,136 @@ func AddQuickStylePath(path string) {
 	defer C.free(unsafe.Pointer(cPath))
 	C.AddQuickStylePath(cPath)
 }
+
+// AvailableQuickStyles returns the names of the available styles, i.e. the
+// built-in styles and the styles found in the style paths.
+func AvailableQuickStyles() []string {
+	var count C.int
+	cStyles := C.AvailableQuickStyles(&count)
+	return goStringSlice(cStyles, count)
+}
+
+// QuickStyleName returns the name of the application style, which might be set by
+// SetQuickStyle, QT_QUICK_CONTROLS_STYLE environment variable or qtquickcontrols2.conf.
+func QuickStyleName() string {
+	cName := C.QuickStyleName()
+	defer C.free(unsafe.Pointer(cName))
+	return C.GoString(cName)
+}
+
+// ValidateQuickStyle checks if the application style is available. Call it before loading
+// QML, since Qt Quick Controls 2 silently uses the default style if the style doesn't exist.
+// The available styles are only searched in the style paths, so styles that Qt resolves in
+// other ways (e.g. static builds or styles from QML import paths) may be reported missing.
+func ValidateQuickStyle() error {
+	return validateQuickStyle(QuickStyleName())
+}
+
+// validateQuickStyle checks if the style is an available style. Path to custom style,
+// either file path or Qt resource, is not validated.
+func validateQuickStyle(style string) error {
+	if style == "" || strings.ContainsAny(style, `/\:`) {
+		return nil
+	}
+
+	available := AvailableQuickStyles()
+	for _, name := range available {
+		if strings.EqualFold(name, style) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("style %q is not available, available styles are %s",
+		style, strings.Join(available, ", "))
+}
+
+// SetMaterialTheme configures the Material style. If it's called before loading QML, the
+// theme is used as the default for all windows. Otherwise, it's applied to the existing
+// windows that import QtQuick.Controls.Material in their root component. For Viewer, it's
+// applied to the root item. It returns error if the theme can't be applied to a window.
+func SetMaterialTheme(theme MaterialTheme) error {
+	attributes := []styleAttribute{
+		{"theme", "THEME", theme.Theme, true},
+		{"accent", "ACCENT", theme.Accent, false},
+		{"primary", "PRIMARY", theme.Primary, false},
+		{"foreground", "FOREGROUND", theme.Foreground, false},
+		{"background", "BACKGROUND", theme.Background, false},
+	}
+
+	return applyStyleAttributes("Material", attributes)
+}
+
+// SetUniversalTheme configures the Universal style. If it's called before loading QML, the
+// theme is used as the default for all windows. Otherwise, it's applied to the existing
+// windows that import QtQuick.Controls.Universal in their root component. For Viewer, it's
+// applied to the root item. It returns error if the theme can't be applied to a window.
+func SetUniversalTheme(theme UniversalTheme) error {
+	attributes := []styleAttribute{
+		{"theme", "THEME", theme.Theme, true},
+		{"accent", "ACCENT", theme.Accent, false},
+		{"foreground", "FOREGROUND", theme.Foreground, false},
+		{"background", "BACKGROUND", theme.Background, false},
+	}
+
+	return applyStyleAttributes("Universal", attributes)
+}
+
+// styleAttribute is an attribute of Material or Universal style.
+type styleAttribute struct {
+	property string
+	envName  string
+	value    string
+	isTheme  bool
+}
+
+// applyStyleAttributes validates the attributes of the style, then applies it to
+// the environment variables (for QML that loaded later) and to the existing windows.
+func applyStyleAttributes(style string, attributes []styleAttribute) error {
+	var statements []string
+	for _, attr := range attributes {
+		if attr.value == "" {
+			continue
+		}
+
+		var value string
+		switch {
+		case attr.isTheme:
+			switch attr.value {
+			case "Light", "Dark", "System":
+				value = style + "." + attr.value
+			default:
+				return fmt.Errorf("invalid %s theme %q", style, attr.value)
+			}
+		case rxStyleColorHex.MatchString(attr.value):
+			value = `"` + attr.value + `"`
+		case rxStyleColorName.MatchString(attr.value):
+			value = style + "." + attr.value
+		default:
+			return fmt.Errorf("invalid %s %s %q", style, attr.property, attr.value)
+		}
+
+		statements = append(statements, fmt.Sprintf("%s.%s = %s", style, attr.property, value))
+	}
+
+	for _, attr := range attributes {
+		if attr.value != "" {
+			envName := "QT_QUICK_CONTROLS_" + strings.ToUpper(style) + "_" + attr.envName
+			os.Setenv(envName, attr.value)
+		}
+	}
+
+	if len(statements) == 0 {
+		return nil
+	}
+
+	// The statements are skipped for windows that don't import the style
+	script := fmt.Sprintf(`if (typeof %s !== "undefined") { %s }`, style, strings.Join(statements, "; "))
+	cScript := C.CString(script)
+	defer C.free(unsafe.Pointer(cScript))
+
+	if err := goError(C.ApplyQuickStyleAttributes(cScript)); err != nil {
+		return fmt.Errorf("failed to apply %s style: %v", style, err)
+	}
+
+	return nil
+}
@@ -10,6 +10,9 @@ extern "C" {
 void SetQuickStyle(char* style);
 void SetQuickStyleFallback(char* style);
 void AddQuickStylePath(char* style);
+char** AvailableQuickStyles(int* count);
+char* QuickStyleName();
+char* ApplyQuickStyleAttributes(char* script);
 
 #ifdef __cplusplus
 }
@@ -0,0 +1,88 @@
+#include "_cgo_export.h"
+#include "screen.h"
+#include "utils.h"
+#include <QGuiApplication>
+#include <QScreen>
+#include <QRect>
+#include <QList>
+#include <QVariant>
+
+QamelScreen qamelScreenInfo(QScreen *screen) {
+    QamelScreen info;
+    memset(&info, 0, sizeof(info));
+    if (screen == nullptr) {
+        return info;
+    }
+
+    QRect geometry = screen->geometry();
+    QRect available = screen->availableGeometry();
+
+    info.name = qamelCString(screen->name());
+    info.x = geometry.x();
+    info.y = geometry.y();
+    info.width = geometry.width();
+    info.height = geometry.height();
+    info.availableX = available.x();
+    info.availableY = available.y();
+    info.availableWidth = available.width();
+    info.availableHeight = available.height();
+    info.logicalDpiX = screen->logicalDotsPerInchX();
+    info.logicalDpiY = screen->logicalDotsPerInchY();
+    info.physicalDpiX = screen->physicalDotsPerInchX();
+    info.physicalDpiY = screen->physicalDotsPerInchY();
+    info.devicePixelRatio = screen->devicePixelRatio();
+    info.refreshRate = screen->refreshRate();
+    info.orientation = int(screen->orientation());
+    return info;
+}
+
+QamelScreen* Screen_Screens(int* count) {
+    QamelScreen* result = nullptr;
+    *count = 0;
+
+    qamelRunOnGuiThread(qApp, [&]() {
+        QList<QScreen*> screens = QGuiApplication::screens();
+        if (screens.isEmpty()) {
+            return;
+        }
+
+        result = static_cast<QamelScreen*>(malloc(screens.size() * sizeof(QamelScreen)));
+        for (int i = 0; i < screens.size(); i++) {
+            result[i] = qamelScreenInfo(screens.at(i));
+        }
+        *count = screens.size();
+    });
+
+    return result;
+}
+
+QamelScreen Screen_PrimaryScreen() {
+    QamelScreen result;
+    qamelRunOnGuiThread(qApp, [&]() {
+        result = qamelScreenInfo(QGuiApplication::primaryScreen());
+    });
+
+    return result;
+}
+
+bool Screen_Watch() {
+    if (qApp == nullptr) {
+        return false;
+    }
+
+    qamelRunOnGuiThread(qApp, [&]() {
+        if (qApp->property("_qamelScreenWatched").toBool()) {
+            return;
+        }
+
+        qApp->setProperty("_qamelScreenWatched", true);
+        QObject::connect(qApp, &QGuiApplication::screenAdded, qApp, [](QScreen *screen) {
+            qamelScreenAdded(qamelScreenInfo(screen));
+        });
+        QObject::connect(qApp, &QGuiApplication::screenRemoved, qApp, [](QScreen *screen) {
+            qamelScreenRemoved(qamelScreenInfo(screen));
+        });
+    });
+
+    return true;
+}
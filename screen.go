@@ -0,0 +1,255 @@
+package qamel
+
+// #include <stdlib.h>
+// #include "screen.h"
+import "C"
+import (
+	"sync"
+	"unsafe"
+)
+
+// ScreenOrientation is the orientation of a screen.
+type ScreenOrientation int32
+
+const (
+	// PrimaryOrientation is the display's primary orientation.
+	PrimaryOrientation ScreenOrientation = 0x00000000
+	// PortraitOrientation is the orientation where the height is bigger than the width.
+	PortraitOrientation = 0x00000001
+	// LandscapeOrientation is the orientation where the width is bigger than the height.
+	LandscapeOrientation = 0x00000002
+	// InvertedPortraitOrientation is portrait orientation rotated 180 degrees.
+	InvertedPortraitOrientation = 0x00000004
+	// InvertedLandscapeOrientation is landscape orientation rotated 180 degrees.
+	InvertedLandscapeOrientation = 0x00000008
+)
+
+var (
+	screenMutex           = sync.RWMutex{}
+	screenAddedCallback   func(screen Screen)
+	screenRemovedCallback func(screen Screen)
+)
+
+// Rect is a rectangle in the screen coordinate.
+type Rect struct {
+	X      int
+	Y      int
+	Width  int
+	Height int
+}
+
+// Contains checks if point x, y is inside the rectangle.
+func (r Rect) Contains(x, y int) bool {
+	return x >= r.X && x < r.X+r.Width && y >= r.Y && y < r.Y+r.Height
+}
+
+// Screen is the information of a screen that connected to the system.
+type Screen struct {
+	// Name is the human-readable name of the screen.
+	Name string
+
+	// Geometry is the geometry of the screen in pixels.
+	Geometry Rect
+
+	// AvailableGeometry is the geometry of the screen excluding
+	// the window manager reserved areas, e.g. task bars and system menus.
+	AvailableGeometry Rect
+
+	// LogicalDpiX and LogicalDpiY is the number of logical dots per inch, which
+	// is used by the system to scale fonts. It might be different with the
+	// physical DPI, e.g. when user changes the scaling in the system settings.
+	LogicalDpiX float64
+	LogicalDpiY float64
+
+	// PhysicalDpiX and PhysicalDpiY is the number of physical dots per inch,
+	// which is computed from the physical size of the screen.
+	PhysicalDpiX float64
+	PhysicalDpiY float64
+
+	// DevicePixelRatio is the ratio between physical pixels and device-independent
+	// pixels of the screen, e.g. 2 on Retina display.
+	DevicePixelRatio float64
+
+	// RefreshRate is the approximate vertical refresh rate of the screen in Hz.
+	RefreshRate float64
+
+	// Orientation is the current orientation of the screen.
+	Orientation ScreenOrientation
+}
+
+// windowGeometry is the saved geometry and state of a window.
+type windowGeometry struct {
+	X      int          `json:"x"`
+	Y      int          `json:"y"`
+	Width  int          `json:"width"`
+	Height int          `json:"height"`
+	States WindowStates `json:"states"`
+	Screen string       `json:"screen"`
+}
+
+// goScreen converts C screen into Go screen. The name of C screen will be freed.
+func goScreen(cScreen C.QamelScreen) Screen {
+	screen := Screen{
+		Name: C.GoString(cScreen.name),
+		Geometry: Rect{
+			X:      int(cScreen.x),
+			Y:      int(cScreen.y),
+			Width:  int(cScreen.width),
+			Height: int(cScreen.height),
+		},
+		AvailableGeometry: Rect{
+			X:      int(cScreen.availableX),
+			Y:      int(cScreen.availableY),
+			Width:  int(cScreen.availableWidth),
+			Height: int(cScreen.availableHeight),
+		},
+		LogicalDpiX:      float64(cScreen.logicalDpiX),
+		LogicalDpiY:      float64(cScreen.logicalDpiY),
+		PhysicalDpiX:     float64(cScreen.physicalDpiX),
+		PhysicalDpiY:     float64(cScreen.physicalDpiY),
+		DevicePixelRatio: float64(cScreen.devicePixelRatio),
+		RefreshRate:      float64(cScreen.refreshRate),
+		Orientation:      ScreenOrientation(cScreen.orientation),
+	}
+
+	C.free(unsafe.Pointer(cScreen.name))
+	return screen
+}
+
+// screens returns list of screens that connected to the system.
+func screens() []Screen {
+	var count C.int
+	cScreens := C.Screen_Screens(&count)
+	if cScreens == nil {
+		return nil
+	}
+	defer C.free(unsafe.Pointer(cScreens))
+
+	n := int(count)
+	items := (*[1 << 20]C.QamelScreen)(unsafe.Pointer(cScreens))[:n:n]
+	result := make([]Screen, n)
+	for i, item := range items {
+		result[i] = goScreen(item)
+	}
+
+	return result
+}
+
+// Screens returns list of screens that connected to the system.
+// The first screen is the primary screen.
+func (app Application) Screens() []Screen {
+	return screens()
+}
+
+// PrimaryScreen returns the primary screen of the system,
+// which is where the windows shown by default.
+func (app Application) PrimaryScreen() Screen {
+	return goScreen(C.Screen_PrimaryScreen())
+}
+
+// OnScreenAdded registers callback that will be called when a new screen connected to
+// the system. The callback is called in the GUI thread. Pass nil to remove the callback.
+// It returns ErrNotInitialized if the application is not constructed yet.
+func (app Application) OnScreenAdded(callback func(screen Screen)) error {
+	if callback != nil && !bool(C.Screen_Watch()) {
+		return ErrNotInitialized
+	}
+
+	screenMutex.Lock()
+	screenAddedCallback = callback
+	screenMutex.Unlock()
+	return nil
+}
+
+// OnScreenRemoved registers callback that will be called when a screen disconnected from
+// the system. The callback is called in the GUI thread. Pass nil to remove the callback.
+// It returns ErrNotInitialized if the application is not constructed yet.
+func (app Application) OnScreenRemoved(callback func(screen Screen)) error {
+	if callback != nil && !bool(C.Screen_Watch()) {
+		return ErrNotInitialized
+	}
+
+	screenMutex.Lock()
+	screenRemovedCallback = callback
+	screenMutex.Unlock()
+	return nil
+}
+
+//export qamelScreenAdded
+func qamelScreenAdded(cScreen C.QamelScreen) {
+	screen := goScreen(cScreen)
+
+	screenMutex.RLock()
+	callback := screenAddedCallback
+	screenMutex.RUnlock()
+
+	if callback != nil {
+		callback(screen)
+	}
+}
+
+//export qamelScreenRemoved
+func qamelScreenRemoved(cScreen C.QamelScreen) {
+	screen := goScreen(cScreen)
+
+	screenMutex.RLock()
+	callback := screenRemovedCallback
+	screenMutex.RUnlock()
+
+	if callback != nil {
+		callback(screen)
+	}
+}
+
+// clampToScreens moves and resizes the rect so it fits inside the available geometry of
+// one of the screens. The screen that contains the center of the rect is preferred,
+// followed by the screen with the specified name, then the first screen.
+func clampToScreens(rect Rect, screenName string, screens []Screen) Rect {
+	if len(screens) == 0 {
+		return rect
+	}
+
+	// Find the target screen
+	target, found := Screen{}, false
+	centerX, centerY := rect.X+rect.Width/2, rect.Y+rect.Height/2
+	for _, screen := range screens {
+		if screen.AvailableGeometry.Contains(centerX, centerY) {
+			target, found = screen, true
+			break
+		}
+	}
+
+	for i := 0; i < len(screens) && !found; i++ {
+		if screens[i].Name == screenName {
+			target, found = screens[i], true
+		}
+	}
+
+	if !found {
+		target = screens[0]
+	}
+
+	// Fit the size, then the position
+	area := target.AvailableGeometry
+	if rect.Width > area.Width {
+		rect.Width = area.Width
+	}
+
+	if rect.Height > area.Height {
+		rect.Height = area.Height
+	}
+
+	if rect.X < area.X {
+		rect.X = area.X
+	} else if rect.X+rect.Width > area.X+area.Width {
+		rect.X = area.X + area.Width - rect.Width
+	}
+
+	if rect.Y < area.Y {
+		rect.Y = area.Y
+	} else if rect.Y+rect.Height > area.Y+area.Height {
+		rect.Y = area.Y + area.Height - rect.Height
+	}
+
+	return rect
+}
@@ -0,0 +1,38 @@
+#pragma once
+
+#ifndef QAMEL_SCREEN_H
+#define QAMEL_SCREEN_H
+
+#include <stdint.h>
+#include <stdbool.h>
+
+// QamelScreen is the information of a screen.
+// The name is allocated by malloc and must be freed.
+typedef struct {
+    char* name;
+    int x, y, width, height;
+    int availableX, availableY, availableWidth, availableHeight;
+    double logicalDpiX, logicalDpiY, physicalDpiX, physicalDpiY;
+    double devicePixelRatio, refreshRate;
+    int orientation;
+} QamelScreen;
+
+#ifdef __cplusplus
+
+#include <QScreen>
+
+// qamelScreenInfo converts QScreen into QamelScreen.
+QamelScreen qamelScreenInfo(QScreen *screen);
+
+extern "C" {
+#endif
+
+QamelScreen* Screen_Screens(int* count);
+QamelScreen Screen_PrimaryScreen();
+bool Screen_Watch();
+
+#ifdef __cplusplus
+}
+#endif
+
+#endif
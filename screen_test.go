@@ -0,0 +1,68 @@
+package qamel
+
+import "testing"
+
+func TestClampToScreens(t *testing.T) {
+	screens := []Screen{
+		{Name: "left", AvailableGeometry: Rect{X: 0, Y: 0, Width: 1920, Height: 1040}},
+		{Name: "right", AvailableGeometry: Rect{X: 1920, Y: 0, Width: 1280, Height: 984}},
+	}
+
+	tests := []struct {
+		name       string
+		rect       Rect
+		screenName string
+		screens    []Screen
+		expected   Rect
+	}{{
+		name:     "no screens",
+		rect:     Rect{X: -500, Y: -500, Width: 800, Height: 600},
+		expected: Rect{X: -500, Y: -500, Width: 800, Height: 600},
+	}, {
+		name:     "inside screen",
+		rect:     Rect{X: 100, Y: 100, Width: 800, Height: 600},
+		screens:  screens,
+		expected: Rect{X: 100, Y: 100, Width: 800, Height: 600},
+	}, {
+		name:     "center in second screen",
+		rect:     Rect{X: 2000, Y: 100, Width: 800, Height: 600},
+		screens:  screens,
+		expected: Rect{X: 2000, Y: 100, Width: 800, Height: 600},
+	}, {
+		name:     "overflow right edge",
+		rect:     Rect{X: 2700, Y: 100, Width: 800, Height: 600},
+		screens:  screens,
+		expected: Rect{X: 2400, Y: 100, Width: 800, Height: 600},
+	}, {
+		name:     "overflow top left",
+		rect:     Rect{X: -100, Y: -50, Width: 800, Height: 600},
+		screens:  screens,
+		expected: Rect{X: 0, Y: 0, Width: 800, Height: 600},
+	}, {
+		name:     "larger than screen",
+		rect:     Rect{X: 2000, Y: 0, Width: 1600, Height: 1200},
+		screens:  screens,
+		expected: Rect{X: 1920, Y: 0, Width: 1280, Height: 984},
+	}, {
+		name:       "disconnected screen uses saved screen name",
+		rect:       Rect{X: 5000, Y: 100, Width: 800, Height: 600},
+		screenName: "right",
+		screens:    screens,
+		expected:   Rect{X: 2400, Y: 100, Width: 800, Height: 600},
+	}, {
+		name:       "unknown screen uses first screen",
+		rect:       Rect{X: 5000, Y: 2000, Width: 800, Height: 600},
+		screenName: "missing",
+		screens:    screens,
+		expected:   Rect{X: 1120, Y: 440, Width: 800, Height: 600},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := clampToScreens(test.rect, test.screenName, test.screens)
+			if result != test.expected {
+				t.Errorf("expected %+v, got %+v", test.expected, result)
+			}
+		})
+	}
+}
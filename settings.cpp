@@ -0,0 +1,359 @@
+#include "_cgo_export.h"
+#include "settings.h"
+#include "utils.h"
+#include "handle.h"
+#include <QGuiApplication>
+#include <QQmlEngine>
+#include <QSettings>
+#include <QString>
+#include <QStringList>
+#include <QVariant>
+#include <QJsonDocument>
+#include <QJsonArray>
+#include <QJsonValue>
+
+QamelSettingsNotifier* QamelSettingsNotifier::instance() {
+    static QamelSettingsNotifier notifier;
+    return &notifier;
+}
+
+QamelSettings::QamelSettings(QObject *parent) : QObject(parent) {
+    _settings = new QSettings(this);
+    init();
+}
+
+QamelSettings::QamelSettings(const QString &fileName, QSettings::Format format, QObject *parent) : QObject(parent) {
+    _settings = new QSettings(fileName, format, this);
+    init();
+}
+
+void QamelSettings::init() {
+    // Forward the changes that made in the same settings file, either by this or other object
+    connect(QamelSettingsNotifier::instance(), &QamelSettingsNotifier::changed, this,
+        [this](const QString &fileName, const QString &key, const QVariant &value) {
+            if (fileName == _settings->fileName()) {
+                emit valueChanged(key, value);
+            }
+        });
+
+    connect(this, &QamelSettings::valueChanged, this, [this](const QString &key) {
+        QByteArray cKey = key.toUtf8();
+        qamelSettingsChanged(this, cKey.data());
+    });
+}
+
+QSettings* QamelSettings::settings() {
+    return _settings;
+}
+
+QString QamelSettings::fileName() const {
+    return _settings->fileName();
+}
+
+QVariant QamelSettings::value(const QString &key, const QVariant &defaultValue) const {
+    return _settings->value(key, defaultValue);
+}
+
+void QamelSettings::setValue(const QString &key, const QVariant &value) {
+    _settings->setValue(key, value);
+
+    QString group = _settings->group();
+    QString fullKey = group.isEmpty() ? key : group + "/" + key;
+    emit QamelSettingsNotifier::instance()->changed(_settings->fileName(), fullKey, value);
+}
+
+bool QamelSettings::contains(const QString &key) const {
+    return _settings->contains(key);
+}
+
+void QamelSettings::remove(const QString &key) {
+    _settings->remove(key);
+
+    QString group = _settings->group();
+    QString fullKey = group.isEmpty() ? key : key.isEmpty() ? group : group + "/" + key;
+    emit QamelSettingsNotifier::instance()->changed(_settings->fileName(), fullKey, QVariant());
+}
+
+QStringList QamelSettings::allKeys() const {
+    return _settings->allKeys();
+}
+
+void QamelSettings::sync() {
+    _settings->sync();
+}
+
+void Settings_SetDefaultFormat(int format) {
+    QSettings::setDefaultFormat(QSettings::Format(format));
+}
+
+void Settings_RegisterQML(char* uri, int versionMajor, int versionMinor, char* qmlName) {
+    qmlRegisterType<QamelSettings>(uri, versionMajor, versionMinor, qmlName);
+}
+
+void* Settings_NewSettings() {
+    QamelSettings *settings = nullptr;
+    qamelRunOnGuiThread(qApp, [&]() { settings = new QamelSettings(); });
+    return settings;
+}
+
+void* Settings_NewSettingsFile(char* fileName, int format) {
+    QString settingsFile(fileName);
+    QamelSettings *settings = nullptr;
+    qamelRunOnGuiThread(qApp, [&]() {
+        settings = new QamelSettings(settingsFile, QSettings::Format(format));
+    });
+    return settings;
+}
+
+void Settings_Destroy(void* ptr) {
+    static_cast<QamelSettings*>(ptr)->deleteLater();
+}
+
+char* Settings_FileName(void* ptr, uint64_t id) {
+    QString fileName;
+    qamelRunOnHandle<QamelSettings>(ptr, id, [&](QamelSettings *settings) {
+        fileName = settings->fileName();
+    });
+    return qamelCString(fileName);
+}
+
+bool Settings_Contains(void* ptr, uint64_t id, char* key) {
+    QString settingsKey(key);
+    bool result = false;
+    qamelRunOnHandle<QamelSettings>(ptr, id, [&](QamelSettings *settings) {
+        result = settings->contains(settingsKey);
+    });
+    return result;
+}
+
+char* Settings_Value(void* ptr, uint64_t id, char* key) {
+    QString settingsKey(key);
+    QVariant value;
+    bool found = false;
+
+    qamelRunOnHandle<QamelSettings>(ptr, id, [&](QamelSettings *settings) {
+        found = settings->contains(settingsKey);
+        value = settings->value(settingsKey);
+    });
+
+    if (!found) {
+        return nullptr;
+    }
+
+    // Wrap the value in array, since JSON document can't contain scalar value
+    QJsonArray array;
+    array.append(QJsonValue::fromVariant(value));
+    return qamelCString(QString::fromUtf8(QJsonDocument(array).toJson(QJsonDocument::Compact)));
+}
+
+char* Settings_String(void* ptr, uint64_t id, char* key, bool* found) {
+    QString settingsKey(key);
+    QString value;
+    *found = false;
+
+    qamelRunOnHandle<QamelSettings>(ptr, id, [&](QamelSettings *settings) {
+        *found = settings->contains(settingsKey);
+        value = settings->value(settingsKey).toString();
+    });
+
+    return qamelCString(value);
+}
+
+long long Settings_Int(void* ptr, uint64_t id, char* key, bool* ok) {
+    QString settingsKey(key);
+    long long value = 0;
+    *ok = false;
+
+    qamelRunOnHandle<QamelSettings>(ptr, id, [&](QamelSettings *settings) {
+        if (settings->contains(settingsKey)) {
+            value = settings->value(settingsKey).toLongLong(ok);
+        }
+    });
+
+    return value;
+}
+
+double Settings_Float(void* ptr, uint64_t id, char* key, bool* ok) {
+    QString settingsKey(key);
+    double value = 0;
+    *ok = false;
+
+    qamelRunOnHandle<QamelSettings>(ptr, id, [&](QamelSettings *settings) {
+        if (settings->contains(settingsKey)) {
+            value = settings->value(settingsKey).toDouble(ok);
+        }
+    });
+
+    return value;
+}
+
+bool Settings_Bool(void* ptr, uint64_t id, char* key, bool* found) {
+    QString settingsKey(key);
+    bool value = false;
+    *found = false;
+
+    qamelRunOnHandle<QamelSettings>(ptr, id, [&](QamelSettings *settings) {
+        *found = settings->contains(settingsKey);
+        value = settings->value(settingsKey).toBool();
+    });
+
+    return value;
+}
+
+char** Settings_Strings(void* ptr, uint64_t id, char* key, int* count) {
+    QString settingsKey(key);
+    QStringList values;
+    qamelRunOnHandle<QamelSettings>(ptr, id, [&](QamelSettings *settings) {
+        values = settings->value(settingsKey).toStringList();
+    });
+    return qamelCStringArray(values, count);
+}
+
+char* Settings_SetValue(void* ptr, uint64_t id, char* key, char* jsonValue, bool* live) {
+    QString settingsKey(key);
+
+    QJsonParseError parseError;
+    QJsonDocument doc = QJsonDocument::fromJson(QByteArray(jsonValue), &parseError);
+    if (parseError.error != QJsonParseError::NoError || !doc.isArray() || doc.array().isEmpty()) {
+        return qamelCString("invalid value: " + parseError.errorString());
+    }
+
+    QVariant value = doc.array().first().toVariant();
+    *live = qamelRunOnHandle<QamelSettings>(ptr, id, [&](QamelSettings *settings) {
+        settings->setValue(settingsKey, value);
+    });
+    return nullptr;
+}
+
+bool Settings_SetString(void* ptr, uint64_t id, char* key, char* value) {
+    QString settingsKey(key);
+    QString settingsValue(value);
+    return qamelRunOnHandle<QamelSettings>(ptr, id, [&](QamelSettings *settings) {
+        settings->setValue(settingsKey, settingsValue);
+    });
+}
+
+bool Settings_SetInt(void* ptr, uint64_t id, char* key, long long value) {
+    QString settingsKey(key);
+    return qamelRunOnHandle<QamelSettings>(ptr, id, [&](QamelSettings *settings) {
+        settings->setValue(settingsKey, qlonglong(value));
+    });
+}
+
+bool Settings_SetFloat(void* ptr, uint64_t id, char* key, double value) {
+    QString settingsKey(key);
+    return qamelRunOnHandle<QamelSettings>(ptr, id, [&](QamelSettings *settings) {
+        settings->setValue(settingsKey, value);
+    });
+}
+
+bool Settings_SetBool(void* ptr, uint64_t id, char* key, bool value) {
+    QString settingsKey(key);
+    return qamelRunOnHandle<QamelSettings>(ptr, id, [&](QamelSettings *settings) {
+        settings->setValue(settingsKey, value);
+    });
+}
+
+bool Settings_SetStrings(void* ptr, uint64_t id, char* key, char** values, int count) {
+    QString settingsKey(key);
+    QStringList settingsValues = qamelStringList(values, count);
+    return qamelRunOnHandle<QamelSettings>(ptr, id, [&](QamelSettings *settings) {
+        settings->setValue(settingsKey, settingsValues);
+    });
+}
+
+bool Settings_Remove(void* ptr, uint64_t id, char* key) {
+    QString settingsKey(key);
+    return qamelRunOnHandle<QamelSettings>(ptr, id, [&](QamelSettings *settings) {
+        settings->remove(settingsKey);
+    });
+}
+
+bool Settings_Clear(void* ptr, uint64_t id) {
+    return qamelRunOnHandle<QamelSettings>(ptr, id, [&](QamelSettings *settings) {
+        settings->settings()->clear();
+        emit QamelSettingsNotifier::instance()->changed(settings->fileName(), QString(), QVariant());
+    });
+}
+
+char** Settings_AllKeys(void* ptr, uint64_t id, int* count) {
+    QStringList keys;
+    qamelRunOnHandle<QamelSettings>(ptr, id, [&](QamelSettings *settings) {
+        keys = settings->allKeys();
+    });
+    return qamelCStringArray(keys, count);
+}
+
+char** Settings_ChildKeys(void* ptr, uint64_t id, int* count) {
+    QStringList keys;
+    qamelRunOnHandle<QamelSettings>(ptr, id, [&](QamelSettings *settings) {
+        keys = settings->settings()->childKeys();
+    });
+    return qamelCStringArray(keys, count);
+}
+
+char** Settings_ChildGroups(void* ptr, uint64_t id, int* count) {
+    QStringList groups;
+    qamelRunOnHandle<QamelSettings>(ptr, id, [&](QamelSettings *settings) {
+        groups = settings->settings()->childGroups();
+    });
+    return qamelCStringArray(groups, count);
+}
+
+bool Settings_BeginGroup(void* ptr, uint64_t id, char* prefix) {
+    QString groupPrefix(prefix);
+    return qamelRunOnHandle<QamelSettings>(ptr, id, [&](QamelSettings *settings) {
+        settings->settings()->beginGroup(groupPrefix);
+    });
+}
+
+bool Settings_EndGroup(void* ptr, uint64_t id) {
+    return qamelRunOnHandle<QamelSettings>(ptr, id, [&](QamelSettings *settings) {
+        settings->settings()->endGroup();
+    });
+}
+
+char* Settings_Group(void* ptr, uint64_t id) {
+    QString group;
+    qamelRunOnHandle<QamelSettings>(ptr, id, [&](QamelSettings *settings) {
+        group = settings->settings()->group();
+    });
+    return qamelCString(group);
+}
+
+bool Settings_BeginReadArray(void* ptr, uint64_t id, char* prefix, int* size) {
+    QString arrayPrefix(prefix);
+    *size = 0;
+    return qamelRunOnHandle<QamelSettings>(ptr, id, [&](QamelSettings *settings) {
+        *size = settings->settings()->beginReadArray(arrayPrefix);
+    });
+}
+
+bool Settings_BeginWriteArray(void* ptr, uint64_t id, char* prefix, int size) {
+    QString arrayPrefix(prefix);
+    return qamelRunOnHandle<QamelSettings>(ptr, id, [&](QamelSettings *settings) {
+        settings->settings()->beginWriteArray(arrayPrefix, size);
+    });
+}
+
+bool Settings_SetArrayIndex(void* ptr, uint64_t id, int i) {
+    return qamelRunOnHandle<QamelSettings>(ptr, id, [&](QamelSettings *settings) {
+        settings->settings()->setArrayIndex(i);
+    });
+}
+
+bool Settings_EndArray(void* ptr, uint64_t id) {
+    return qamelRunOnHandle<QamelSettings>(ptr, id, [&](QamelSettings *settings) {
+        settings->settings()->endArray();
+    });
+}
+
+bool Settings_Sync(void* ptr, uint64_t id, int* status) {
+    *status = 0;
+    return qamelRunOnHandle<QamelSettings>(ptr, id, [&](QamelSettings *settings) {
+        settings->sync();
+        *status = int(settings->settings()->status());
+    });
+}
+
+#include "moc-settings.h"
@@ -0,0 +1,421 @@
+package qamel
+
+// #include <stdint.h>
+// #include <stdlib.h>
+// #include <stdbool.h>
+// #include "settings.h"
+import "C"
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"unsafe"
+)
+
+// SettingsFormat is the storage format of settings.
+type SettingsFormat int32
+
+const (
+	// NativeFormat stores the settings using the most appropriate storage format
+	// for the platform. On Windows, this means the system registry; on macOS, this
+	// means the CFPreferences API; on Unix, this means textual configuration files
+	// in INI format.
+	NativeFormat SettingsFormat = 0
+	// IniFormat stores the settings in INI files.
+	IniFormat = 1
+)
+
+var (
+	settingsCallbackMutex = sync.RWMutex{}
+	mapSettingsCallback   = map[unsafe.Pointer]func(key string){}
+)
+
+// Settings is persistent platform-independent application settings, which wraps QSettings.
+// The settings are stored by organization name and application name, so make sure to set
+// both of them before creating the settings. All settings that use the same file, including
+// the ones created in QML using the type registered by RegisterQmlSettings, share the same
+// values. The group and array state is shared by all goroutines, so don't use it concurrently.
+type Settings struct {
+	ptr unsafe.Pointer
+	id  uint64
+}
+
+// SetDefaultSettingsFormat sets the format that used by NewSettings and by settings in QML.
+func SetDefaultSettingsFormat(format SettingsFormat) {
+	C.Settings_SetDefaultFormat(C.int(format))
+}
+
+// RegisterQmlSettings registers Settings as QML object. In QML, it has method value(key,
+// defaultValue), setValue(key, value), contains(key), remove(key), allKeys() and sync(),
+// and signal valueChanged(key, value). It uses the default settings, same as NewSettings.
+func RegisterQmlSettings(uri string, versionMajor int, versionMinor int, qmlName string) {
+	cURI := C.CString(uri)
+	cQmlName := C.CString(qmlName)
+	cVersionMajor := C.int(int32(versionMajor))
+	cVersionMinor := C.int(int32(versionMinor))
+	defer func() {
+		C.free(unsafe.Pointer(cURI))
+		C.free(unsafe.Pointer(cQmlName))
+	}()
+
+	C.Settings_RegisterQML(cURI, cVersionMajor, cVersionMinor, cQmlName)
+}
+
+// NewSettings creates the settings for the current organization and application,
+// using the default format. The application must be constructed first.
+func NewSettings() Settings {
+	ptr := C.Settings_NewSettings()
+	return Settings{ptr: ptr, id: registerHandle(ptr, settingsHandle)}
+}
+
+// NewSettingsFile creates the settings that stored in the specified file and format.
+// If the file doesn't exist, it will be created.
+func NewSettingsFile(fileName string, format SettingsFormat) Settings {
+	cFileName := C.CString(fileName)
+	defer C.free(unsafe.Pointer(cFileName))
+
+	ptr := C.Settings_NewSettingsFile(cFileName, C.int(format))
+	return Settings{ptr: ptr, id: registerHandle(ptr, settingsHandle)}
+}
+
+// err returns the error if the native settings is not live anymore.
+func (s Settings) err() error {
+	return handleError(s.ptr, s.id)
+}
+
+// Destroy writes the unsaved changes and deletes the native settings. After this, the
+// settings and all of its copies become invalid: methods that return error will return
+// ErrDestroyed and the getters return zero values or the default value.
+func (s Settings) Destroy() error {
+	if !releaseHandle(s.ptr, s.id) {
+		return s.err()
+	}
+
+	C.Settings_Destroy(s.ptr)
+	return nil
+}
+
+// FileName returns the path where settings are stored. On Windows, if the format
+// is NativeFormat, the return value is a system registry path, not a file path.
+func (s Settings) FileName() string {
+	cFileName := C.Settings_FileName(s.ptr, C.uint64_t(s.id))
+	defer C.free(unsafe.Pointer(cFileName))
+	return C.GoString(cFileName)
+}
+
+// Contains checks if there exists a setting called key.
+func (s Settings) Contains(key string) bool {
+	cKey := C.CString(key)
+	defer C.free(unsafe.Pointer(cKey))
+	return bool(C.Settings_Contains(s.ptr, C.uint64_t(s.id), cKey))
+}
+
+// Value returns the value of setting key. The value is converted using JSON, so number
+// will be float64, array will be []interface{} and map will be map[string]interface{}.
+// Note that INI format stores most values as string. The second return value is false
+// if the setting doesn't exist.
+func (s Settings) Value(key string) (interface{}, bool) {
+	cKey := C.CString(key)
+	defer C.free(unsafe.Pointer(cKey))
+
+	cValue := C.Settings_Value(s.ptr, C.uint64_t(s.id), cKey)
+	if cValue == nil {
+		return nil, false
+	}
+	defer C.free(unsafe.Pointer(cValue))
+
+	var values []interface{}
+	if err := json.Unmarshal([]byte(C.GoString(cValue)), &values); err != nil || len(values) == 0 {
+		return nil, true
+	}
+
+	return values[0], true
+}
+
+// String returns the value of setting key as string, or defaultValue if it doesn't exist.
+func (s Settings) String(key string, defaultValue string) string {
+	cKey := C.CString(key)
+	defer C.free(unsafe.Pointer(cKey))
+
+	var found C.bool
+	cValue := C.Settings_String(s.ptr, C.uint64_t(s.id), cKey, &found)
+	defer C.free(unsafe.Pointer(cValue))
+
+	if !found {
+		return defaultValue
+	}
+
+	return C.GoString(cValue)
+}
+
+// Int returns the value of setting key as int, or defaultValue if
+// it doesn't exist or can't be converted to int.
+func (s Settings) Int(key string, defaultValue int) int {
+	cKey := C.CString(key)
+	defer C.free(unsafe.Pointer(cKey))
+
+	var ok C.bool
+	value := C.Settings_Int(s.ptr, C.uint64_t(s.id), cKey, &ok)
+	if !ok {
+		return defaultValue
+	}
+
+	return int(value)
+}
+
+// Float returns the value of setting key as float64, or defaultValue if
+// it doesn't exist or can't be converted to float64.
+func (s Settings) Float(key string, defaultValue float64) float64 {
+	cKey := C.CString(key)
+	defer C.free(unsafe.Pointer(cKey))
+
+	var ok C.bool
+	value := C.Settings_Float(s.ptr, C.uint64_t(s.id), cKey, &ok)
+	if !ok {
+		return defaultValue
+	}
+
+	return float64(value)
+}
+
+// Bool returns the value of setting key as bool, or defaultValue if it doesn't exist.
+// String value is false if it's empty, "0" or "false", and true otherwise.
+func (s Settings) Bool(key string, defaultValue bool) bool {
+	cKey := C.CString(key)
+	defer C.free(unsafe.Pointer(cKey))
+
+	var found C.bool
+	value := C.Settings_Bool(s.ptr, C.uint64_t(s.id), cKey, &found)
+	if !found {
+		return defaultValue
+	}
+
+	return bool(value)
+}
+
+// Strings returns the value of setting key as string slice.
+func (s Settings) Strings(key string) []string {
+	cKey := C.CString(key)
+	defer C.free(unsafe.Pointer(cKey))
+
+	var count C.int
+	cValues := C.Settings_Strings(s.ptr, C.uint64_t(s.id), cKey, &count)
+	return goStringSlice(cValues, count)
+}
+
+// SetValue sets the value of setting key to value. The value is converted using JSON,
+// so it could be any value that can be marshaled to JSON. If the setting already
+// exists, the previous value is overwritten.
+func (s Settings) SetValue(key string, value interface{}) error {
+	bt, err := json.Marshal([]interface{}{value})
+	if err != nil {
+		return fmt.Errorf("failed to encode value: %v", err)
+	}
+
+	cKey := C.CString(key)
+	defer C.free(unsafe.Pointer(cKey))
+
+	cValue := C.CString(string(bt))
+	defer C.free(unsafe.Pointer(cValue))
+
+	var live C.bool
+	if err := goError(C.Settings_SetValue(s.ptr, C.uint64_t(s.id), cKey, cValue, &live)); err != nil {
+		return err
+	}
+
+	return nativeError(s.ptr, live)
+}
+
+// SetString sets the value of setting key to string value.
+func (s Settings) SetString(key string, value string) error {
+	cKey := C.CString(key)
+	defer C.free(unsafe.Pointer(cKey))
+
+	cValue := C.CString(value)
+	defer C.free(unsafe.Pointer(cValue))
+
+	return nativeError(s.ptr, C.Settings_SetString(s.ptr, C.uint64_t(s.id), cKey, cValue))
+}
+
+// SetInt sets the value of setting key to int value.
+func (s Settings) SetInt(key string, value int) error {
+	cKey := C.CString(key)
+	defer C.free(unsafe.Pointer(cKey))
+	return nativeError(s.ptr, C.Settings_SetInt(s.ptr, C.uint64_t(s.id), cKey, C.longlong(value)))
+}
+
+// SetFloat sets the value of setting key to float64 value.
+func (s Settings) SetFloat(key string, value float64) error {
+	cKey := C.CString(key)
+	defer C.free(unsafe.Pointer(cKey))
+	return nativeError(s.ptr, C.Settings_SetFloat(s.ptr, C.uint64_t(s.id), cKey, C.double(value)))
+}
+
+// SetBool sets the value of setting key to bool value.
+func (s Settings) SetBool(key string, value bool) error {
+	cKey := C.CString(key)
+	defer C.free(unsafe.Pointer(cKey))
+	return nativeError(s.ptr, C.Settings_SetBool(s.ptr, C.uint64_t(s.id), cKey, C.bool(value)))
+}
+
+// SetStrings sets the value of setting key to string slice.
+func (s Settings) SetStrings(key string, values []string) error {
+	cKey := C.CString(key)
+	defer C.free(unsafe.Pointer(cKey))
+
+	cValues, free := cStringArray(values)
+	defer free()
+
+	return nativeError(s.ptr, C.Settings_SetStrings(s.ptr, C.uint64_t(s.id), cKey, cValues, C.int(int32(len(values)))))
+}
+
+// Remove removes the setting key and any sub-settings of key. If key is empty,
+// all keys in the current group are removed.
+func (s Settings) Remove(key string) error {
+	cKey := C.CString(key)
+	defer C.free(unsafe.Pointer(cKey))
+	return nativeError(s.ptr, C.Settings_Remove(s.ptr, C.uint64_t(s.id), cKey))
+}
+
+// Clear removes all entries in the settings.
+func (s Settings) Clear() error {
+	return nativeError(s.ptr, C.Settings_Clear(s.ptr, C.uint64_t(s.id)))
+}
+
+// AllKeys returns a list of all keys, including sub-keys, that can be read.
+func (s Settings) AllKeys() []string {
+	var count C.int
+	cKeys := C.Settings_AllKeys(s.ptr, C.uint64_t(s.id), &count)
+	return goStringSlice(cKeys, count)
+}
+
+// ChildKeys returns a list of all top-level keys in the current group.
+func (s Settings) ChildKeys() []string {
+	var count C.int
+	cKeys := C.Settings_ChildKeys(s.ptr, C.uint64_t(s.id), &count)
+	return goStringSlice(cKeys, count)
+}
+
+// ChildGroups returns a list of all key top-level groups in the current group.
+func (s Settings) ChildGroups() []string {
+	var count C.int
+	cGroups := C.Settings_ChildGroups(s.ptr, C.uint64_t(s.id), &count)
+	return goStringSlice(cGroups, count)
+}
+
+// BeginGroup appends prefix to the current group. The current group is automatically
+// prepended to all keys specified to Settings. Call EndGroup to reset the current group.
+func (s Settings) BeginGroup(prefix string) error {
+	cPrefix := C.CString(prefix)
+	defer C.free(unsafe.Pointer(cPrefix))
+	return nativeError(s.ptr, C.Settings_BeginGroup(s.ptr, C.uint64_t(s.id), cPrefix))
+}
+
+// EndGroup resets the group to what it was before the corresponding BeginGroup call.
+func (s Settings) EndGroup() error {
+	return nativeError(s.ptr, C.Settings_EndGroup(s.ptr, C.uint64_t(s.id)))
+}
+
+// Group returns the current group.
+func (s Settings) Group() string {
+	cGroup := C.Settings_Group(s.ptr, C.uint64_t(s.id))
+	defer C.free(unsafe.Pointer(cGroup))
+	return C.GoString(cGroup)
+}
+
+// BeginReadArray adds prefix to the current group and starts reading from an array.
+// It returns the size of the array. Use SetArrayIndex to choose the item to read.
+func (s Settings) BeginReadArray(prefix string) (int, error) {
+	cPrefix := C.CString(prefix)
+	defer C.free(unsafe.Pointer(cPrefix))
+
+	var size C.int
+	if err := nativeError(s.ptr, C.Settings_BeginReadArray(s.ptr, C.uint64_t(s.id), cPrefix, &size)); err != nil {
+		return 0, err
+	}
+
+	return int(size), nil
+}
+
+// BeginWriteArray adds prefix to the current group and starts writing an array of
+// the specified size. If size is -1, it's determined from the index of the last
+// written item. Use SetArrayIndex to choose the item to write.
+func (s Settings) BeginWriteArray(prefix string, size int) error {
+	cPrefix := C.CString(prefix)
+	defer C.free(unsafe.Pointer(cPrefix))
+	return nativeError(s.ptr, C.Settings_BeginWriteArray(s.ptr, C.uint64_t(s.id), cPrefix, C.int(int32(size))))
+}
+
+// SetArrayIndex sets the current array index to i. Calls to methods such as
+// SetValue, Value, Remove and Contains will operate on the array entry at that index.
+func (s Settings) SetArrayIndex(i int) error {
+	return nativeError(s.ptr, C.Settings_SetArrayIndex(s.ptr, C.uint64_t(s.id), C.int(int32(i))))
+}
+
+// EndArray closes the array that was started using BeginReadArray or BeginWriteArray.
+func (s Settings) EndArray() error {
+	return nativeError(s.ptr, C.Settings_EndArray(s.ptr, C.uint64_t(s.id)))
+}
+
+// Sync writes any unsaved changes to permanent storage, and reloads any settings that
+// have been changed in the meantime by another application. It's called automatically
+// when the settings destroyed and by the event loop at regular intervals, so normally
+// there is no need to call it. It returns error if the settings can't be saved or loaded.
+func (s Settings) Sync() error {
+	var status C.int
+	if err := nativeError(s.ptr, C.Settings_Sync(s.ptr, C.uint64_t(s.id), &status)); err != nil {
+		return err
+	}
+
+	switch status {
+	case 1:
+		return fmt.Errorf("failed to access settings file %s", s.FileName())
+	case 2:
+		return fmt.Errorf("settings file %s is malformed", s.FileName())
+	default:
+		return nil
+	}
+}
+
+// OnChanged registers callback that will be called when a setting changed, either by
+// this settings or by other settings (including the ones in QML) that use the same file.
+// The key is the full key including the group. If the settings are cleared, the key is
+// empty. Changes that made by other process are not reported. The callback is called
+// in the GUI thread. Pass nil to remove the callback.
+func (s Settings) OnChanged(callback func(key string)) error {
+	if err := s.err(); err != nil {
+		return err
+	}
+
+	settingsCallbackMutex.Lock()
+	defer settingsCallbackMutex.Unlock()
+
+	// The entry is kept when the callback removed, so the cleanup is only registered
+	// once for each settings. It's deleted by the cleanup once the settings destroyed.
+	if _, exist := mapSettingsCallback[s.ptr]; !exist {
+		ptr := s.ptr
+		cleanup := func() {
+			settingsCallbackMutex.Lock()
+			delete(mapSettingsCallback, ptr)
+			settingsCallbackMutex.Unlock()
+		}
+
+		if _, ok := addHandleCleanup(s.ptr, s.id, cleanup); !ok {
+			return s.err()
+		}
+	}
+
+	mapSettingsCallback[s.ptr] = callback
+	return nil
+}
+
+//export qamelSettingsChanged
+func qamelSettingsChanged(ptr unsafe.Pointer, cKey *C.char) {
+	settingsCallbackMutex.RLock()
+	callback := mapSettingsCallback[ptr]
+	settingsCallbackMutex.RUnlock()
+
+	if callback != nil {
+		callback(C.GoString(cKey))
+	}
+}
@@ -0,0 +1,106 @@
+#pragma once
+
+#ifndef QAMEL_SETTINGS_H
+#define QAMEL_SETTINGS_H
+
+#include <stdint.h>
+#include <stdbool.h>
+
+#ifdef __cplusplus
+
+#include <QObject>
+#include <QSettings>
+#include <QString>
+#include <QStringList>
+#include <QVariant>
+
+// QamelSettingsNotifier broadcasts the changes made by any QamelSettings,
+// so all instances that use the same settings file are notified.
+class QamelSettingsNotifier : public QObject
+{
+    Q_OBJECT
+
+public:
+    static QamelSettingsNotifier* instance();
+
+signals:
+    void changed(const QString &fileName, const QString &key, const QVariant &value);
+};
+
+class QamelSettings : public QObject
+{
+    Q_OBJECT
+    Q_PROPERTY(QString fileName READ fileName CONSTANT)
+
+public:
+    QamelSettings(QObject *parent = nullptr);
+    QamelSettings(const QString &fileName, QSettings::Format format, QObject *parent = nullptr);
+
+    QSettings* settings();
+    QString fileName() const;
+
+public slots:
+    QVariant value(const QString &key, const QVariant &defaultValue = QVariant()) const;
+    void setValue(const QString &key, const QVariant &value);
+    bool contains(const QString &key) const;
+    void remove(const QString &key);
+    QStringList allKeys() const;
+    void sync();
+
+signals:
+    void valueChanged(const QString &key, const QVariant &value);
+
+private:
+    void init();
+
+    QSettings *_settings;
+};
+
+extern "C" {
+#endif // __cplusplus
+
+// Static functions
+void Settings_SetDefaultFormat(int format);
+void Settings_RegisterQML(char* uri, int versionMajor, int versionMinor, char* qmlName);
+
+// Constructors
+void* Settings_NewSettings();
+void* Settings_NewSettingsFile(char* fileName, int format);
+
+// Destructor
+void Settings_Destroy(void* ptr);
+
+// Methods
+char* Settings_FileName(void* ptr, uint64_t id);
+bool Settings_Contains(void* ptr, uint64_t id, char* key);
+char* Settings_Value(void* ptr, uint64_t id, char* key);
+char* Settings_String(void* ptr, uint64_t id, char* key, bool* found);
+long long Settings_Int(void* ptr, uint64_t id, char* key, bool* ok);
+double Settings_Float(void* ptr, uint64_t id, char* key, bool* ok);
+bool Settings_Bool(void* ptr, uint64_t id, char* key, bool* found);
+char** Settings_Strings(void* ptr, uint64_t id, char* key, int* count);
+char* Settings_SetValue(void* ptr, uint64_t id, char* key, char* jsonValue, bool* live);
+bool Settings_SetString(void* ptr, uint64_t id, char* key, char* value);
+bool Settings_SetInt(void* ptr, uint64_t id, char* key, long long value);
+bool Settings_SetFloat(void* ptr, uint64_t id, char* key, double value);
+bool Settings_SetBool(void* ptr, uint64_t id, char* key, bool value);
+bool Settings_SetStrings(void* ptr, uint64_t id, char* key, char** values, int count);
+bool Settings_Remove(void* ptr, uint64_t id, char* key);
+bool Settings_Clear(void* ptr, uint64_t id);
+char** Settings_AllKeys(void* ptr, uint64_t id, int* count);
+char** Settings_ChildKeys(void* ptr, uint64_t id, int* count);
+char** Settings_ChildGroups(void* ptr, uint64_t id, int* count);
+bool Settings_BeginGroup(void* ptr, uint64_t id, char* prefix);
+bool Settings_EndGroup(void* ptr, uint64_t id);
+char* Settings_Group(void* ptr, uint64_t id);
+bool Settings_BeginReadArray(void* ptr, uint64_t id, char* prefix, int* size);
+bool Settings_BeginWriteArray(void* ptr, uint64_t id, char* prefix, int size);
+bool Settings_SetArrayIndex(void* ptr, uint64_t id, int i);
+bool Settings_EndArray(void* ptr, uint64_t id);
+bool Settings_Sync(void* ptr, uint64_t id, int* status);
+
+#ifdef __cplusplus
+}
+#endif
+
+#endif
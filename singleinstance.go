@@ -0,0 +1,203 @@
+package qamel
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	fp "path/filepath"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// singleInstanceTimeout is the timeout for sending the arguments to the primary instance.
+const singleInstanceTimeout = 5 * time.Second
+
+var (
+	instanceMutex    = sync.Mutex{}
+	instanceAppReady = false
+	instanceMessages = []func(){}
+)
+
+// EnsureSingleInstance makes sure there is only one instance of the application with the
+// specified id, e.g. the reverse domain name of the application. It's done using local
+// socket in a directory that only accessible by the current user ($XDG_RUNTIME_DIR if
+// available), so it only works for processes of the same user on the same machine.
+//
+// If there are no other instance, this process becomes the primary instance and it returns
+// true. Every time another instance started, onMessage will be called in the GUI thread
+// with the command line arguments of that instance (excluding the program name). Messages
+// that received before the application constructed are delivered once its event loop runs.
+//
+// If the primary instance already exists, the command line arguments of this process are
+// forwarded to it and it returns false, in which case the caller should exit immediately.
+// It should be called early, before constructing the application and creating the windows.
+func EnsureSingleInstance(id string, onMessage func(args []string)) (primary bool, err error) {
+	if id == "" {
+		return false, fmt.Errorf("instance id must not be empty")
+	}
+
+	dir, err := singleInstanceDir()
+	if err != nil {
+		return false, fmt.Errorf("failed to prepare instance dir: %v", err)
+	}
+
+	primary, closer, err := startSingleInstance(dir, id, os.Args[1:], onMessage)
+	if err != nil || !primary {
+		return primary, err
+	}
+
+	aboutToQuitMutex.Lock()
+	aboutToQuitCallbacks = append(aboutToQuitCallbacks, closer)
+	aboutToQuitMutex.Unlock()
+
+	return true, nil
+}
+
+// startSingleInstance becomes the primary instance for id, using the socket and lock file
+// inside dir. If the primary instance already exists, args is sent to it instead. For the
+// primary instance, it returns closer that stops listening and releases the lock.
+func startSingleInstance(dir, id string, args []string, onMessage func(args []string)) (bool, func(), error) {
+	hash := sha1.Sum([]byte(id))
+	baseName := fp.Join(dir, "qamel-"+hex.EncodeToString(hash[:8]))
+	socketPath := baseName + ".sock"
+
+	// The lock is held by the primary instance as long as it's alive, so instances that
+	// started at the same time are serialized, and the socket left by crashed primary
+	// instance (whose lock is released by the OS) can be told apart from the live one.
+	lockFile, locked, err := lockInstanceFile(baseName + ".lock")
+	if err != nil {
+		return false, nil, fmt.Errorf("failed to lock instance: %v", err)
+	}
+
+	if !locked {
+		if err = sendInstanceMessage(socketPath, args); err != nil {
+			return false, nil, fmt.Errorf("failed to send arguments to primary instance: %v", err)
+		}
+		return false, nil, nil
+	}
+
+	// Since the lock is held, the existing socket must be left by crashed primary instance
+	os.Remove(socketPath)
+
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		lockFile.Close()
+		return false, nil, fmt.Errorf("failed to listen to %s: %v", socketPath, err)
+	}
+
+	go serveInstanceMessages(listener, onMessage)
+
+	closer := func() {
+		listener.Close()
+		lockFile.Close()
+	}
+
+	return true, closer, nil
+}
+
+// sendInstanceMessage sends the arguments to the primary instance, then waits until
+// it's acknowledged. The primary instance may still be starting, i.e. it has held the
+// lock but not listening yet, so the connection is retried until timeout.
+func sendInstanceMessage(socketPath string, args []string) error {
+	deadline := time.Now().Add(singleInstanceTimeout)
+
+	var conn net.Conn
+	var err error
+	for {
+		conn, err = net.DialTimeout("unix", socketPath, time.Until(deadline))
+		if err == nil {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			return err
+		}
+
+		time.Sleep(50 * time.Millisecond)
+	}
+	defer conn.Close()
+
+	conn.SetDeadline(deadline)
+
+	if args == nil {
+		args = []string{}
+	}
+
+	if err = json.NewEncoder(conn).Encode(args); err != nil {
+		return fmt.Errorf("failed to send arguments: %v", err)
+	}
+
+	if _, err = bufio.NewReader(conn).ReadByte(); err != nil {
+		return fmt.Errorf("primary instance doesn't respond: %v", err)
+	}
+
+	return nil
+}
+
+// serveInstanceMessages accepts the arguments from other instances
+// until the listener is closed.
+func serveInstanceMessages(listener net.Listener, onMessage func(args []string)) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+
+		go func(conn net.Conn) {
+			defer conn.Close()
+			conn.SetDeadline(time.Now().Add(singleInstanceTimeout))
+
+			var args []string
+			if err := json.NewDecoder(conn).Decode(&args); err != nil {
+				logrus.Warnln("Invalid message from other instance:", err)
+				return
+			}
+
+			if onMessage != nil {
+				queueInstanceMessage(func() { onMessage(args) })
+			}
+
+			conn.Write([]byte{1})
+		}(conn)
+	}
+}
+
+// queueInstanceMessage runs f in the GUI thread. RunOnGuiThread can't be used before
+// the application constructed, so f is queued until then.
+func queueInstanceMessage(f func()) {
+	instanceMutex.Lock()
+	if !instanceAppReady {
+		instanceMessages = append(instanceMessages, f)
+		instanceMutex.Unlock()
+		return
+	}
+	instanceMutex.Unlock()
+
+	RunOnGuiThread(f)
+}
+
+// flushInstanceMessages marks the application as constructed, then runs the queued
+// messages in the GUI thread once its event loop runs.
+func flushInstanceMessages() {
+	instanceMutex.Lock()
+	instanceAppReady = true
+	messages := instanceMessages
+	instanceMessages = nil
+	instanceMutex.Unlock()
+
+	if len(messages) == 0 {
+		return
+	}
+
+	go func() {
+		for _, f := range messages {
+			RunOnGuiThread(f)
+		}
+	}()
+}
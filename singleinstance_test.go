@@ -0,0 +1,110 @@
+package qamel
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	fp "path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestSingleInstanceHelper is not a real test. It's run by the other tests in a separate
+// process, acting as another instance of the application.
+func TestSingleInstanceHelper(t *testing.T) {
+	mode := os.Getenv("QAMEL_INSTANCE_HELPER")
+	if mode == "" {
+		t.Skip("only run as helper process")
+	}
+
+	primary, _, err := startSingleInstance(os.Getenv("QAMEL_INSTANCE_DIR"),
+		"com.example.test", []string{"open", "file.txt"}, func([]string) {})
+
+	// Exit without closing the socket, like a crashed instance
+	fmt.Printf("primary=%v err=%v\n", primary, err)
+	os.Exit(0)
+}
+
+func runInstanceHelper(t *testing.T, dir string) string {
+	cmd := exec.Command(os.Args[0], "-test.run=^TestSingleInstanceHelper$")
+	cmd.Env = append(os.Environ(), "QAMEL_INSTANCE_HELPER=1", "QAMEL_INSTANCE_DIR="+dir)
+
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("helper process failed: %v", err)
+	}
+
+	return strings.TrimSpace(string(out))
+}
+
+func takeInstanceMessages() []func() {
+	instanceMutex.Lock()
+	defer instanceMutex.Unlock()
+
+	messages := instanceMessages
+	instanceMessages = nil
+	return messages
+}
+
+func TestSingleInstanceForwarding(t *testing.T) {
+	dir, err := ioutil.TempDir("", "qamel-instance")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var received []string
+	primary, closer, err := startSingleInstance(dir, "com.example.test", nil, func(args []string) {
+		received = args
+	})
+	if err != nil || !primary {
+		t.Fatalf("expected primary instance, got primary=%v err=%v", primary, err)
+	}
+	defer closer()
+
+	if out := runInstanceHelper(t, dir); out != "primary=false err=<nil>" {
+		t.Fatalf("expected secondary instance, got %q", out)
+	}
+
+	// The application is not constructed, so the message must be queued
+	messages := takeInstanceMessages()
+	if len(messages) != 1 {
+		t.Fatalf("expected 1 queued message, got %d", len(messages))
+	}
+
+	messages[0]()
+	if expected := []string{"open", "file.txt"}; !reflect.DeepEqual(received, expected) {
+		t.Errorf("expected arguments %v, got %v", expected, received)
+	}
+}
+
+func TestSingleInstanceStaleSocket(t *testing.T) {
+	dir, err := ioutil.TempDir("", "qamel-instance")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// The helper becomes primary, then exits without removing its socket
+	if out := runInstanceHelper(t, dir); out != "primary=true err=<nil>" {
+		t.Fatalf("expected primary instance, got %q", out)
+	}
+
+	sockets, _ := fp.Glob(fp.Join(dir, "*.sock"))
+	if len(sockets) != 1 {
+		t.Fatalf("expected stale socket, got %v", sockets)
+	}
+
+	primary, closer, err := startSingleInstance(dir, "com.example.test", nil, nil)
+	if err != nil || !primary {
+		t.Fatalf("expected primary instance, got primary=%v err=%v", primary, err)
+	}
+	defer closer()
+
+	// The socket must be usable by the next instance
+	if out := runInstanceHelper(t, dir); out != "primary=false err=<nil>" {
+		t.Fatalf("expected secondary instance, got %q", out)
+	}
+}
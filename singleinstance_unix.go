@@ -0,0 +1,73 @@
+//go:build !windows
+// +build !windows
+
+package qamel
+
+import (
+	"fmt"
+	"os"
+	fp "path/filepath"
+	"syscall"
+)
+
+// singleInstanceDir returns the directory for the socket of single instance, which is
+// $XDG_RUNTIME_DIR if available, otherwise a directory inside the temp dir that only
+// accessible by the current user.
+func singleInstanceDir() (string, error) {
+	uid := os.Getuid()
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" && checkPrivateDir(dir, uid) == nil {
+		return dir, nil
+	}
+
+	dir := fp.Join(os.TempDir(), fmt.Sprintf("qamel-%d", uid))
+	if err := os.Mkdir(dir, 0700); err != nil && !os.IsExist(err) {
+		return "", err
+	}
+
+	if err := checkPrivateDir(dir, uid); err != nil {
+		return "", err
+	}
+
+	return dir, nil
+}
+
+// checkPrivateDir makes sure dir is a real directory that owned by uid and not accessible
+// by other users, so the socket inside it can't be replaced or connected by them.
+func checkPrivateDir(dir string, uid int) error {
+	info, err := os.Lstat(dir)
+	if err != nil {
+		return err
+	}
+
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	switch {
+	case !info.IsDir():
+		return fmt.Errorf("%s is not a directory", dir)
+	case !ok || int(stat.Uid) != uid:
+		return fmt.Errorf("%s is not owned by the current user", dir)
+	case info.Mode().Perm()&0077 != 0:
+		return fmt.Errorf("%s is accessible by other users", dir)
+	default:
+		return nil
+	}
+}
+
+// lockInstanceFile acquires the exclusive lock of the file in path without waiting. It
+// returns false if the lock is held by other instance. The lock is released once the
+// returned file is closed or the process exits.
+func lockInstanceFile(path string) (*os.File, bool, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if err = syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		f.Close()
+		if err == syscall.EWOULDBLOCK {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+
+	return f, true, nil
+}
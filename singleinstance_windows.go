@@ -0,0 +1,50 @@
+//go:build windows
+// +build windows
+
+package qamel
+
+import (
+	"os"
+	fp "path/filepath"
+	"syscall"
+)
+
+// errSharingViolation is returned by CreateFile when the file is opened by other process.
+const errSharingViolation syscall.Errno = 32
+
+// singleInstanceDir returns the directory for the socket of single instance, which is
+// inside the local app data of the current user.
+func singleInstanceDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	dir = fp.Join(dir, "qamel")
+	if err = os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+
+	return dir, nil
+}
+
+// lockInstanceFile opens the file in path without sharing, which works as exclusive lock.
+// It returns false if the file is opened by other instance. The lock is released once the
+// returned file is closed or the process exits.
+func lockInstanceFile(path string) (*os.File, bool, error) {
+	cPath, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return nil, false, err
+	}
+
+	handle, err := syscall.CreateFile(cPath, syscall.GENERIC_READ|syscall.GENERIC_WRITE,
+		0, nil, syscall.OPEN_ALWAYS, syscall.FILE_ATTRIBUTE_NORMAL, 0)
+	if err != nil {
+		if err == errSharingViolation {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+
+	return os.NewFile(uintptr(handle), path), true, nil
+}
@@ -0,0 +1,110 @@
+#include "translation.h"
+#include "utils.h"
+#include "window.h"
+#include <QGuiApplication>
+#include <QTranslator>
+#include <QLocale>
+#include <QFileInfo>
+#include <QDir>
+#include <QSet>
+#include <QPointer>
+#include <QQmlEngine>
+#include <QWindow>
+
+static QPointer<QTranslator> qamelTranslator;
+
+// qamelOriginalLocale is the default locale before it's changed by the first translation,
+// which is restored once the translation removed.
+static QLocale *qamelOriginalLocale = nullptr;
+
+// qamelTranslationFile finds the translation file for the locale inside dir, e.g. for
+// locale "pt-BR" it looks for pt_BR.qm, then pt.qm. Returns empty string if not found.
+static QString qamelTranslationFile(const QLocale &locale, const QDir &dir) {
+    for (QString language : locale.uiLanguages()) {
+        language.replace('-', '_');
+        while (!language.isEmpty()) {
+            QString fileName = dir.filePath(language + ".qm");
+            if (QFileInfo::exists(fileName)) {
+                return fileName;
+            }
+
+            int idx = language.lastIndexOf('_');
+            language = idx > 0 ? language.left(idx) : QString();
+        }
+    }
+
+    return QString();
+}
+
+char* Translation_Load(char* locale, char* path) {
+    QString localeName(locale);
+    QString translationPath(path);
+    QString errorMessage;
+
+    qamelRunOnGuiThread(qApp, [&]() {
+        // Find the translation file
+        QString fileName;
+        if (!localeName.isEmpty()) {
+            if (translationPath.endsWith(".qm")) {
+                fileName = translationPath;
+            } else {
+                fileName = qamelTranslationFile(QLocale(localeName), QDir(translationPath));
+            }
+
+            if (fileName.isEmpty()) {
+                errorMessage = "no translation for " + localeName + " in " + translationPath;
+                return;
+            }
+        }
+
+        // Load the new translator before removing the old one,
+        // so the old translation is kept if the new one failed.
+        QTranslator *translator = nullptr;
+        if (!fileName.isEmpty()) {
+            translator = new QTranslator(qApp);
+            if (!translator->load(fileName)) {
+                delete translator;
+                errorMessage = "failed to load translation " + fileName;
+                return;
+            }
+        }
+
+        if (qamelTranslator != nullptr) {
+            QCoreApplication::removeTranslator(qamelTranslator);
+            delete qamelTranslator;
+        }
+
+        qamelTranslator = translator;
+        if (translator != nullptr) {
+            if (qamelOriginalLocale == nullptr) {
+                qamelOriginalLocale = new QLocale();
+            }
+
+            QCoreApplication::installTranslator(translator);
+            QLocale::setDefault(QLocale(localeName));
+        } else if (qamelOriginalLocale != nullptr) {
+            QLocale::setDefault(*qamelOriginalLocale);
+        }
+
+        Translation_Retranslate();
+    });
+
+    if (errorMessage.isEmpty()) {
+        return nullptr;
+    }
+
+    return qamelCString(errorMessage);
+}
+
+void Translation_Retranslate() {
+    qamelRunOnGuiThread(qApp, [&]() {
+        QSet<QQmlEngine*> engines;
+        for (QWindow *window : QGuiApplication::topLevelWindows()) {
+            QQmlEngine *engine = qamelWindowEngine(window);
+            if (engine != nullptr && !engines.contains(engine)) {
+                engines.insert(engine);
+                engine->retranslate();
+            }
+        }
+    });
+}
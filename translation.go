@@ -0,0 +1,30 @@
+package qamel
+
+// #include <stdlib.h>
+// #include "translation.h"
+import "C"
+import "unsafe"
+
+// LoadTranslation loads the translation for the locale (e.g. "id" or "pt_BR"), replacing the
+// translation that loaded previously, then retranslates all QML windows so the language can
+// be switched at runtime. The path is either a *.qm file or a directory that contains the
+// translation files named by the locale, e.g. "qrc:/res/translations" which is where
+// `qamel build` puts the compiled translations. In directory, the file for more specific
+// locale is preferred, so "pt_BR" loads pt_BR.qm, or pt.qm if it doesn't exist.
+// Use empty locale to remove the translation and go back to the original language.
+func (app Application) LoadTranslation(locale string, path string) error {
+	cLocale := C.CString(locale)
+	defer C.free(unsafe.Pointer(cLocale))
+
+	cPath := C.CString(localPath(path))
+	defer C.free(unsafe.Pointer(cPath))
+
+	return goError(C.Translation_Load(cLocale, cPath))
+}
+
+// Retranslate refreshes all bindings in QML that use strings marked for translation.
+// It's called automatically by LoadTranslation, so normally there is no need to call it,
+// unless the translators are installed manually.
+func (app Application) Retranslate() {
+	C.Translation_Retranslate()
+}
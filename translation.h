@@ -0,0 +1,17 @@
+#pragma once
+
+#ifndef QAMEL_TRANSLATION_H
+#define QAMEL_TRANSLATION_H
+
+#ifdef __cplusplus
+extern "C" {
+#endif
+
+char* Translation_Load(char* locale, char* path);
+void Translation_Retranslate();
+
+#ifdef __cplusplus
+}
+#endif
+
+#endif
@@ -0,0 +1,91 @@
+package qamel
+
+// #include <stdlib.h>
+import "C"
+import (
+	"errors"
+	"strings"
+	"unsafe"
+)
+
+// ErrNotInitialized is returned when method is called on object
+// whose native counterpart has not been initialized.
+var ErrNotInitialized = errors.New("qamel: object is not initialized")
+
+// cStringArray converts Go string slice into C array of C string. The returned
+// function must be called to free the array once it's not used anymore.
+func cStringArray(strs []string) (**C.char, func()) {
+	if len(strs) == 0 {
+		return nil, func() {}
+	}
+
+	ptrSize := unsafe.Sizeof((*C.char)(nil))
+	cArray := (**C.char)(C.malloc(C.size_t(len(strs)) * C.size_t(ptrSize)))
+	items := (*[1 << 28]*C.char)(unsafe.Pointer(cArray))[:len(strs):len(strs)]
+	for i, str := range strs {
+		items[i] = C.CString(str)
+	}
+
+	free := func() {
+		for i := range items {
+			C.free(unsafe.Pointer(items[i]))
+		}
+		C.free(unsafe.Pointer(cArray))
+	}
+
+	return cArray, free
+}
+
+// goStringSlice converts C array of C string that allocated with malloc
+// into Go string slice. The C array and its content will be freed.
+func goStringSlice(cArray **C.char, count C.int) []string {
+	if cArray == nil || count <= 0 {
+		return nil
+	}
+
+	n := int(count)
+	items := (*[1 << 28]*C.char)(unsafe.Pointer(cArray))[:n:n]
+	result := make([]string, n)
+	for i, item := range items {
+		result[i] = C.GoString(item)
+		C.free(unsafe.Pointer(item))
+	}
+
+	C.free(unsafe.Pointer(cArray))
+	return result
+}
+
+// qmlError converts C string that contains QML errors (one error per line)
+// into Go error. The C string will be freed.
+func qmlError(cErrors *C.char) error {
+	if cErrors == nil {
+		return nil
+	}
+
+	defer C.free(unsafe.Pointer(cErrors))
+	return errors.New(strings.TrimSpace(C.GoString(cErrors)))
+}
+
+// goError converts C string that contains error message into Go error.
+// The C string will be freed.
+func goError(cMessage *C.char) error {
+	if cMessage == nil {
+		return nil
+	}
+
+	defer C.free(unsafe.Pointer(cMessage))
+	return errors.New(C.GoString(cMessage))
+}
+
+// cleanFileSelectors removes the leading "+" and the empty names from file selectors.
+func cleanFileSelectors(selectors []string) []string {
+	var result []string
+	for _, selector := range selectors {
+		selector = strings.TrimPrefix(strings.TrimSpace(selector), "+")
+		if selector != "" {
+			result = append(result, selector)
+		}
+	}
+
+	return result
+}
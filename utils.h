@@ -0,0 +1,79 @@
+#pragma once
+
+#ifndef QAMEL_UTILS_H
+#define QAMEL_UTILS_H
+
+#ifdef __cplusplus
+
+#include <stdlib.h>
+#include <string.h>
+#include <QString>
+#include <QStringList>
+#include <QByteArray>
+#include <QObject>
+#include <QThread>
+#include <QMetaObject>
+#include <QList>
+#include <QQmlError>
+
+// qamelCString converts QString into C string which allocated by malloc,
+// so it could be freed from Go using C.free.
+inline char* qamelCString(const QString &str) {
+    return strdup(str.toUtf8().constData());
+}
+
+// qamelCStringArray converts QStringList into C array of C string.
+// The array and its content are allocated by malloc.
+inline char** qamelCStringArray(const QStringList &list, int *count) {
+    *count = list.size();
+    if (list.isEmpty()) {
+        return nullptr;
+    }
+
+    char** result = static_cast<char**>(malloc(list.size() * sizeof(char*)));
+    for (int i = 0; i < list.size(); i++) {
+        result[i] = qamelCString(list.at(i));
+    }
+
+    return result;
+}
+
+// qamelStringList converts C array of C string into QStringList.
+inline QStringList qamelStringList(char** array, int count) {
+    QStringList result;
+    for (int i = 0; i < count; i++) {
+        result.append(QString(array[i]));
+    }
+
+    return result;
+}
+
+// qamelQmlErrors converts list of QML errors into C string, one error per line.
+// Returns nullptr if there are no errors.
+inline char* qamelQmlErrors(const QList<QQmlError> &errors) {
+    if (errors.isEmpty()) {
+        return nullptr;
+    }
+
+    QStringList lines;
+    for (const QQmlError &error : errors) {
+        lines.append(error.toString());
+    }
+
+    return qamelCString(lines.join('\n'));
+}
+
+// qamelRunOnGuiThread runs func in the thread where obj lives, and waits until it finished.
+// If the caller already in that thread or obj is nullptr, func will be called directly.
+template <typename Func>
+inline void qamelRunOnGuiThread(QObject *obj, Func func) {
+    if (obj == nullptr || QThread::currentThread() == obj->thread()) {
+        func();
+    } else {
+        QMetaObject::invokeMethod(obj, func, Qt::BlockingQueuedConnection);
+    }
+}
+
+#endif // __cplusplus
+
+#endif // QAMEL_UTILS_H
@@ -0,0 +1,25 @@
+package qamel
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCleanFileSelectors(t *testing.T) {
+	tests := []struct {
+		selectors []string
+		expected  []string
+	}{
+		{selectors: nil, expected: nil},
+		{selectors: []string{"", "  ", "+"}, expected: nil},
+		{selectors: []string{"+dark", "tablet"}, expected: []string{"dark", "tablet"}},
+		{selectors: []string{" +dark ", "", "android"}, expected: []string{"dark", "android"}},
+	}
+
+	for _, test := range tests {
+		result := cleanFileSelectors(test.selectors)
+		if !reflect.DeepEqual(result, test.expected) {
+			t.Errorf("cleanFileSelectors(%q): expected %q, got %q", test.selectors, test.expected, result)
+		}
+	}
+}
@@ -22,10 +22,11 @@ const (
 	// if they have a parent, and independent windows if they have no parent.
 	Widget WindowFlags = 0x00000000
 
-	// Window indicates that the widget is a window, usually with a window system frame and
+	// TopLevelWindow indicates that the widget is a window, usually with a window system frame and
 	// a title bar, irrespective of whether the widget has a parent or not. Note that it is
-	// not possible to unset this flag if the widget does not have a parent.
-	Window = 0x00000001
+	// not possible to unset this flag if the widget does not have a parent. It was named Window
+	// before, which is now the name of the Window interface, so there is no alias for it.
+	TopLevelWindow = 0x00000001
 
 	// Dialog indicates that the widget is a window that should be decorated as a dialog
 	// (i.e., typically no maximize or minimize buttons in the title bar). This is the default
@@ -33,18 +34,18 @@ const (
 	// another window, or have a parent and used with the QWidget::windowModality property.
 	// If you make it modal, the dialog will prevent other top-level windows in the application
 	// from getting any input. We refer to a top-level window that has a parent as a secondary window.
-	Dialog = 0x00000002 | Window
+	Dialog = 0x00000002 | TopLevelWindow
 
 	// Sheet indicates that the window is a sheet on macOS. Since using a sheet implies window
 	// modality, the recommended way is to use QWidget::setWindowModality(), or QDialog::open(), instead.
-	Sheet = 0x00000004 | Window
+	Sheet = 0x00000004 | TopLevelWindow
 
 	// Drawer indicates that the widget is a drawer on macOS.
 	Drawer = Sheet | Dialog
 
 	// Popup indicates that the widget is a pop-up top-level window, i.e. that it is modal, but has
 	// a window system frame appropriate for pop-up menus.
-	Popup = 0x00000008 | Window
+	Popup = 0x00000008 | TopLevelWindow
 
 	// Tool indicates that the widget is a tool window. A tool window is often a small window with
 	// a smaller than usual title bar and decoration, typically used for collections of tool buttons.
@@ -64,18 +65,18 @@ const (
 	SplashScreen = ToolTip | Dialog
 
 	// Desktop indicates that this widget is the desktop. This is the type for QDesktopWidget.
-	Desktop = 0x00000010 | Window
+	Desktop = 0x00000010 | TopLevelWindow
 
 	// SubWindow indicates that this widget is a sub-window, such as a QMdiSubWindow widget.
 	SubWindow = 0x00000012
 
 	// ForeignWindow indicates that this window object is a handle representing a native platform
 	// window created by another process or by manually using native code.
-	ForeignWindow = 0x00000020 | Window
+	ForeignWindow = 0x00000020 | TopLevelWindow
 
 	// CoverWindow indicates that the window represents a cover window, which is shown when the
 	// application is minimized on some platforms.
-	CoverWindow = 0x00000040 | Window
+	CoverWindow = 0x00000040 | TopLevelWindow
 
 	// MSWindowsFixedSizeDialogHint gives the window a thin dialog border on Windows. This style is
 	// traditionally used for fixed-size dialogs.
@@ -202,3 +203,114 @@ const (
 	// WindowFullScreen makes the window fills the entire screen without any frame around it.
 	WindowFullScreen = 0x00000004
 )
+
+// Visibility specifies whether the window should appear in the windowing system as normal,
+// minimized, maximized, fullscreen or hidden.
+type Visibility int32
+
+const (
+	// VisibilityHidden means the window is not visible in any way, however it may remember
+	// a latent visibility which can be restored by setting VisibilityAutomatic.
+	VisibilityHidden Visibility = 0
+
+	// VisibilityAutomatic means to give the window a default visible state,
+	// which might be fullscreen or windowed depending on the platform.
+	VisibilityAutomatic = 1
+
+	// VisibilityWindowed means the window occupies part of the screen, but not necessarily the
+	// entire screen. This state will occur only on windowing systems which support
+	// showing multiple windows simultaneously.
+	VisibilityWindowed = 2
+
+	// VisibilityMinimized means the window is reduced to an entry or icon on the task bar, dock,
+	// task list or desktop, depending on how the windowing system handles minimized windows.
+	VisibilityMinimized = 3
+
+	// VisibilityMaximized means the window occupies one entire screen, and the titlebar is still visible.
+	VisibilityMaximized = 4
+
+	// VisibilityFullScreen means the window occupies one entire screen, is not resizable, and there
+	// is no titlebar.
+	VisibilityFullScreen = 5
+)
+
+// Key is the code of keyboard key. Besides the constants below, the
+// code of printable ASCII keys is the same as their uppercase ASCII
+// code, e.g. Key('A') for key A and Key('1') for key 1.
+type Key int32
+
+const (
+	// KeyEscape is the Esc key.
+	KeyEscape Key = 0x01000000
+
+	// KeyTab is the Tab key.
+	KeyTab = 0x01000001
+
+	// KeyBacktab is the Tab key while Shift is pressed.
+	KeyBacktab = 0x01000002
+
+	// KeyBackspace is the Backspace key.
+	KeyBackspace = 0x01000003
+
+	// KeyReturn is the Return key in the main keyboard.
+	KeyReturn = 0x01000004
+
+	// KeyEnter is the Enter key in the keypad.
+	KeyEnter = 0x01000005
+
+	// KeyInsert is the Insert key.
+	KeyInsert = 0x01000006
+
+	// KeyDelete is the Delete key.
+	KeyDelete = 0x01000007
+
+	// KeyHome is the Home key.
+	KeyHome = 0x01000010
+
+	// KeyEnd is the End key.
+	KeyEnd = 0x01000011
+
+	// KeyLeft is the left arrow key.
+	KeyLeft = 0x01000012
+
+	// KeyUp is the up arrow key.
+	KeyUp = 0x01000013
+
+	// KeyRight is the right arrow key.
+	KeyRight = 0x01000014
+
+	// KeyDown is the down arrow key.
+	KeyDown = 0x01000015
+
+	// KeyPageUp is the Page Up key.
+	KeyPageUp = 0x01000016
+
+	// KeyPageDown is the Page Down key.
+	KeyPageDown = 0x01000017
+
+	// KeySpace is the Space key.
+	KeySpace = 0x20
+)
+
+// KeyboardModifiers is the state of modifier keys when a key is pressed.
+type KeyboardModifiers int32
+
+const (
+	// NoModifier means no modifier key is pressed.
+	NoModifier KeyboardModifiers = 0x00000000
+
+	// ShiftModifier means a Shift key on the keyboard is pressed.
+	ShiftModifier = 0x02000000
+
+	// ControlModifier means a Ctrl key on the keyboard is pressed.
+	ControlModifier = 0x04000000
+
+	// AltModifier means an Alt key on the keyboard is pressed.
+	AltModifier = 0x08000000
+
+	// MetaModifier means a Meta key on the keyboard is pressed.
+	MetaModifier = 0x10000000
+
+	// KeypadModifier means a keypad button is pressed.
+	KeypadModifier = 0x20000000
+)
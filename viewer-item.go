@@ -0,0 +1,139 @@
+package qamel
+
+// #include <stdint.h>
+// #include <stdlib.h>
+// #include <stdbool.h>
+// #include "viewer.h"
+import "C"
+import (
+	"encoding/json"
+	"fmt"
+	"unsafe"
+)
+
+// Errors returns the QML errors that occurred during the last load of the view,
+// or nil if there are no errors.
+func (view Viewer) Errors() error {
+	var live C.bool
+	cErrors := C.Viewer_Errors(view.ptr, C.uint64_t(view.id), &live)
+	if err := nativeError(view.ptr, live); err != nil {
+		return err
+	}
+
+	return qmlError(cErrors)
+}
+
+// ItemProperty returns the value of property of the QML item with the specified objectName.
+// The value is converted through JSON, so numbers are returned as float64, lists as
+// []interface{} and objects as map[string]interface{}.
+func (view Viewer) ItemProperty(objectName string, property string) (interface{}, error) {
+	cObjectName := C.CString(objectName)
+	cProperty := C.CString(property)
+	defer func() {
+		C.free(unsafe.Pointer(cObjectName))
+		C.free(unsafe.Pointer(cProperty))
+	}()
+
+	var live C.bool
+	var cError *C.char
+	cResult := C.Viewer_ItemProperty(view.ptr, C.uint64_t(view.id), cObjectName, cProperty, &cError, &live)
+	if err := nativeError(view.ptr, live); err != nil {
+		return nil, err
+	}
+
+	if err := goError(cError); err != nil {
+		return nil, err
+	}
+
+	if cResult == nil {
+		return nil, nil
+	}
+	defer C.free(unsafe.Pointer(cResult))
+
+	var values []interface{}
+	if err := json.Unmarshal([]byte(C.GoString(cResult)), &values); err != nil || len(values) != 1 {
+		return nil, fmt.Errorf("failed to convert property %s of item %s", property, objectName)
+	}
+
+	return values[0], nil
+}
+
+// SetItemProperty sets the value of property of the QML item with the specified objectName.
+// The value is converted through JSON, so it must be serializable by encoding/json.
+func (view Viewer) SetItemProperty(objectName string, property string, value interface{}) error {
+	jsonValue, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to convert value: %v", err)
+	}
+
+	cObjectName := C.CString(objectName)
+	cProperty := C.CString(property)
+	cJSONValue := C.CString(string(jsonValue))
+	defer func() {
+		C.free(unsafe.Pointer(cObjectName))
+		C.free(unsafe.Pointer(cProperty))
+		C.free(unsafe.Pointer(cJSONValue))
+	}()
+
+	var live C.bool
+	cError := C.Viewer_SetItemProperty(view.ptr, C.uint64_t(view.id), cObjectName, cProperty, cJSONValue, &live)
+	if err := nativeError(view.ptr, live); err != nil {
+		return err
+	}
+
+	return goError(cError)
+}
+
+// FocusItem activates the window and gives the active focus to
+// the QML item with the specified objectName.
+func (view Viewer) FocusItem(objectName string) error {
+	cObjectName := C.CString(objectName)
+	defer C.free(unsafe.Pointer(cObjectName))
+	var live C.bool
+	cError := C.Viewer_FocusItem(view.ptr, C.uint64_t(view.id), cObjectName, &live)
+	if err := nativeError(view.ptr, live); err != nil {
+		return err
+	}
+
+	return goError(cError)
+}
+
+// ClickItem sends synthetic left mouse click to the center of
+// the QML item with the specified objectName.
+func (view Viewer) ClickItem(objectName string) error {
+	cObjectName := C.CString(objectName)
+	defer C.free(unsafe.Pointer(cObjectName))
+	var live C.bool
+	cError := C.Viewer_ClickItem(view.ptr, C.uint64_t(view.id), cObjectName, &live)
+	if err := nativeError(view.ptr, live); err != nil {
+		return err
+	}
+
+	return goError(cError)
+}
+
+// SendKey sends synthetic key press and release to the window, which will be
+// delivered to the item that has active focus. The text is the text that
+// generated by the key, e.g. "a" for key A without modifiers.
+func (view Viewer) SendKey(key Key, modifiers KeyboardModifiers, text string) error {
+	cText := C.CString(text)
+	defer C.free(unsafe.Pointer(cText))
+	return nativeError(view.ptr, C.Viewer_SendKey(view.ptr, C.uint64_t(view.id), C.int(key), C.int(modifiers), cText))
+}
+
+// SendText sends synthetic key events for each character in text to the
+// window, as if the text is typed by user.
+func (view Viewer) SendText(text string) error {
+	for _, char := range text {
+		key := Key(char)
+		if char >= 'a' && char <= 'z' {
+			key = Key(char - 'a' + 'A')
+		}
+
+		if err := view.SendKey(key, NoModifier, string(char)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -1,138 +1,602 @@
+#include "_cgo_export.h"
 #include <QQuickView>
 #include <QString>
 #include <QUrl>
 #include <QWindow>
 #include <QIcon>
 #include <QQmlEngine>
+#include <QQmlComponent>
+#include <QByteArray>
 #include <QMetaObject>
+#include <QHash>
+#include <QList>
+#include <QStringList>
+#include <QPointer>
+#include <QQuickItem>
+#include <QQmlError>
+#include <QImage>
+#include <QThread>
+#include <QTimer>
+#include <QSharedPointer>
+#include <QQuickItemGrabResult>
+#include <QVariant>
+#include <QJSValue>
+#include <QJsonArray>
+#include <QJsonDocument>
+#include <QJsonValue>
+#include <QPointF>
+#include <QMouseEvent>
+#include <QKeyEvent>
+#include <QCoreApplication>
+#include <QGuiApplication>
 #include "viewer.h"
+#include "utils.h"
+#include "handle.h"
+#include "hotreload.h"
+#include "overlay.h"
+#include "window.h"
+#include "fileselector.h"
 
 class QamelView : public QQuickView {
     Q_OBJECT
 
 public:
-    QamelView(QWindow *parent = 0) : QQuickView(parent) {}
-    QamelView(const QUrl &source, QWindow *parent = nullptr) : QQuickView(source, parent) {}
+    QamelView(QWindow *parent = 0) : QQuickView(parent) {
+        qamelInstallDevInterceptor(engine());
+
+        connect(this, &QQuickView::statusChanged, this, &QamelView::updateErrorOverlay);
+
+        // Forward window events to Go
+        qamelTrackWindow(this);
+    }
+
+    char* setSourceData(const QByteArray &data, const QUrl &url) {
+        QQmlComponent *component = new QQmlComponent(engine(), this);
+        component->setData(data, url);
+
+        // The component is only loading if it imports remote modules, which can't be created
+        // synchronously. Report it explicitly, since there are no errors yet in that case.
+        QList<QQmlError> loadErrors = component->errors();
+        if (component->isLoading()) {
+            QQmlError error;
+            error.setUrl(url);
+            error.setDescription("remote imports are not supported when loading QML data");
+            loadErrors = QList<QQmlError>() << error;
+        }
+
+        if (component->isError() || component->isLoading()) {
+            showErrorOverlay(loadErrors);
+            char* errors = qamelQmlErrors(loadErrors);
+            delete component;
+            return errors;
+        }
+
+        QObject *item = component->create(rootContext());
+        if (item == nullptr) {
+            QList<QQmlError> createErrors = component->errors();
+            if (createErrors.isEmpty()) {
+                QQmlError error;
+                error.setUrl(url);
+                error.setDescription("failed to create component");
+                createErrors.append(error);
+            }
+
+            showErrorOverlay(createErrors);
+            char* errors = qamelQmlErrors(createErrors);
+            delete component;
+            return errors;
+        }
+
+        // Remove the old root object and component before putting the new one
+        setSource(QUrl());
+
+        _sourceData = data;
+        setContent(url, component, item);
+        return nullptr;
+    }
 
 public slots:
+    void load(const QUrl &url) {
+        _sourceData.clear();
+        setSource(url);
+    }
+
     void reload() {
+        QamelWindowState windowState = QamelWindowState::capture(this);
+        QamelStateSnapshot snapshot;
+        snapshot.capture(QList<QObject*>() << rootObject(), _preserved);
+
         engine()->clearComponentCache();
-        setSource(source());
+        if (_sourceData.isEmpty()) {
+            setSource(source());
+        } else {
+            char* errors = setSourceData(_sourceData, source());
+            free(errors);
+        }
+
+        snapshot.restore(QList<QObject*>() << rootObject());
+        windowState.restore(this);
+    }
+
+public:
+    QQuickItem* findItem(const QString &objectName) {
+        QQuickItem *root = rootObject();
+        if (root == nullptr || root->objectName() == objectName) {
+            return root;
+        }
+
+        return root->findChild<QQuickItem*>(objectName);
+    }
+
+    void preserveProperties(const QString &objectName, const QStringList &properties) {
+        if (properties.isEmpty()) {
+            _preserved.remove(objectName);
+        } else {
+            _preserved.insert(objectName, properties);
+        }
+    }
+
+private:
+    void updateErrorOverlay(QQuickView::Status status) {
+        if (status == QQuickView::Error) {
+            showErrorOverlay(errors());
+        } else if (status == QQuickView::Ready) {
+            delete _errorOverlay;
+        }
+    }
+
+    void showErrorOverlay(const QList<QQmlError> &errors) {
+        delete _errorOverlay;
+        _errorOverlay = qamelCreateErrorOverlay(engine(), errors, contentItem());
     }
+
+    QByteArray _sourceData;
+    QHash<QString, QStringList> _preserved;
+    QPointer<QQuickItem> _errorOverlay;
 };
 
 void* Viewer_NewViewer() {
     return new QamelView();
 }
 
-void Viewer_SetSource(void* ptr, char* url) {
+void Viewer_Close(void* ptr) {
     QamelView *view = static_cast<QamelView*>(ptr);
-    QMetaObject::invokeMethod(view, "setSource", Q_ARG(QUrl, QUrl(QString(url))));
+    qamelRunOnGuiThread(qApp, [&]() {
+        view->close();
+        view->deleteLater();
+    });
 }
 
-void Viewer_SetResizeMode(void* ptr, int resizeMode) {
+void Viewer_Destroy(void* ptr) {
     QamelView *view = static_cast<QamelView*>(ptr);
-    view->setResizeMode(QQuickView::ResizeMode(resizeMode));
+    view->deleteLater();
 }
 
-void Viewer_SetFlags(void* ptr, int flags) {
-    QamelView *view = static_cast<QamelView*>(ptr);
-    view->setFlags(Qt::WindowFlags(flags));
+bool Viewer_SetSource(void* ptr, uint64_t id, char* url) {
+    QUrl sourceURL = QUrl(QString(url));
+    return qamelRunOnHandle<QamelView>(ptr, id, [&](QamelView *view) {
+        view->load(sourceURL);
+    });
 }
 
-void Viewer_SetHeight(void* ptr, int height) {
-    QamelView *view = static_cast<QamelView*>(ptr);
-    view->setHeight(height);
+char* Viewer_SetSourceData(void* ptr, uint64_t id, char* data, int length, char* baseURL, bool* live) {
+    QByteArray qmlData(data, length);
+    QUrl url = QUrl(QString(baseURL));
+
+    char* errors = nullptr;
+    *live = qamelRunOnHandle<QamelView>(ptr, id, [&](QamelView *view) {
+        errors = view->setSourceData(qmlData, url);
+    });
+
+    return errors;
 }
 
-void Viewer_SetWidth(void* ptr, int width) {
-    QamelView *view = static_cast<QamelView*>(ptr);
-    view->setWidth(width);
+bool Viewer_SetResizeMode(void* ptr, uint64_t id, int resizeMode) {
+    return qamelRunOnHandle<QamelView>(ptr, id, [&](QamelView *view) {
+        view->setResizeMode(QQuickView::ResizeMode(resizeMode));
+    });
 }
 
-void Viewer_SetMaximumHeight(void* ptr, int height) {
-    QamelView *view = static_cast<QamelView*>(ptr);
-    view->setMaximumHeight(height);
+bool Viewer_SetFlags(void* ptr, uint64_t id, int flags) {
+    return qamelRunOnHandle<QamelView>(ptr, id, [&](QamelView *view) {
+        view->setFlags(Qt::WindowFlags(flags));
+    });
 }
 
-void Viewer_SetMaximumWidth(void* ptr, int width) {
-    QamelView *view = static_cast<QamelView*>(ptr);
-    view->setMaximumWidth(width);
+bool Viewer_SetHeight(void* ptr, uint64_t id, int height) {
+    return qamelRunOnHandle<QamelView>(ptr, id, [&](QamelView *view) {
+        view->setHeight(height);
+    });
 }
 
-void Viewer_SetMinimumHeight(void* ptr, int height) {
-    QamelView *view = static_cast<QamelView*>(ptr);
-    view->setMinimumHeight(height);
+bool Viewer_SetWidth(void* ptr, uint64_t id, int width) {
+    return qamelRunOnHandle<QamelView>(ptr, id, [&](QamelView *view) {
+        view->setWidth(width);
+    });
 }
 
-void Viewer_SetMinimumWidth(void* ptr, int width) {
-    QamelView *view = static_cast<QamelView*>(ptr);
-    view->setMinimumWidth(width);
+bool Viewer_SetMaximumHeight(void* ptr, uint64_t id, int height) {
+    return qamelRunOnHandle<QamelView>(ptr, id, [&](QamelView *view) {
+        view->setMaximumHeight(height);
+    });
 }
 
-void Viewer_SetOpacity(void* ptr, double opacity) {
-    QamelView *view = static_cast<QamelView*>(ptr);
-    view->setOpacity(opacity);
+bool Viewer_SetMaximumWidth(void* ptr, uint64_t id, int width) {
+    return qamelRunOnHandle<QamelView>(ptr, id, [&](QamelView *view) {
+        view->setMaximumWidth(width);
+    });
 }
 
-void Viewer_SetTitle(void* ptr, char* title) {
-    QamelView *view = static_cast<QamelView*>(ptr);
-    view->setTitle(QString(title));
+bool Viewer_SetMinimumHeight(void* ptr, uint64_t id, int height) {
+    return qamelRunOnHandle<QamelView>(ptr, id, [&](QamelView *view) {
+        view->setMinimumHeight(height);
+    });
 }
 
-void Viewer_SetVisible(void* ptr, bool visible) {
-    QamelView *view = static_cast<QamelView*>(ptr);
-    view->setVisible(visible);
+bool Viewer_SetMinimumWidth(void* ptr, uint64_t id, int width) {
+    return qamelRunOnHandle<QamelView>(ptr, id, [&](QamelView *view) {
+        view->setMinimumWidth(width);
+    });
 }
 
-void Viewer_SetPosition(void* ptr, int x, int y) {
-    QamelView *view = static_cast<QamelView*>(ptr);
-    view->setPosition(x, y);
+bool Viewer_SetOpacity(void* ptr, uint64_t id, double opacity) {
+    return qamelRunOnHandle<QamelView>(ptr, id, [&](QamelView *view) {
+        view->setOpacity(opacity);
+    });
 }
 
-void Viewer_SetIcon(void* ptr, char* fileName) {
-    QamelView *view = static_cast<QamelView*>(ptr);
-    QIcon icon = QIcon(QString(fileName));
-    view->setIcon(icon);
+bool Viewer_SetTitle(void* ptr, uint64_t id, char* title) {
+    QString windowTitle(title);
+    return qamelRunOnHandle<QamelView>(ptr, id, [&](QamelView *view) {
+        view->setTitle(windowTitle);
+    });
 }
 
-void Viewer_Show(void* ptr) {
-    QamelView *view = static_cast<QamelView*>(ptr);
-    view->show();
+bool Viewer_SetVisible(void* ptr, uint64_t id, bool visible) {
+    return qamelRunOnHandle<QamelView>(ptr, id, [&](QamelView *view) {
+        view->setVisible(visible);
+    });
 }
 
-void Viewer_ShowMaximized(void* ptr) {
-    QamelView *view = static_cast<QamelView*>(ptr);
-    view->showMaximized();
+bool Viewer_SetPosition(void* ptr, uint64_t id, int x, int y) {
+    return qamelRunOnHandle<QamelView>(ptr, id, [&](QamelView *view) {
+        view->setPosition(x, y);
+    });
 }
 
-void Viewer_ShowMinimized(void* ptr) {
-    QamelView *view = static_cast<QamelView*>(ptr);
-    view->showMinimized();
+bool Viewer_SetIcon(void* ptr, uint64_t id, char* fileName) {
+    QString iconFile(fileName);
+    return qamelRunOnHandle<QamelView>(ptr, id, [&](QamelView *view) {
+        view->setIcon(QIcon(iconFile));
+    });
 }
 
-void Viewer_ShowFullScreen(void* ptr) {
-    QamelView *view = static_cast<QamelView*>(ptr);
-    view->showFullScreen();
+bool Viewer_Show(void* ptr, uint64_t id) {
+    return qamelRunOnHandle<QamelView>(ptr, id, [&](QamelView *view) {
+        view->show();
+    });
 }
 
-void Viewer_ShowNormal(void* ptr) {
-    QamelView *view = static_cast<QamelView*>(ptr);
-    view->showNormal();
+bool Viewer_ShowMaximized(void* ptr, uint64_t id) {
+    return qamelRunOnHandle<QamelView>(ptr, id, [&](QamelView *view) {
+        view->showMaximized();
+    });
 }
 
-void Viewer_SetWindowStates(void* ptr, int state) {
-    QamelView *view = static_cast<QamelView*>(ptr);
-    view->setWindowStates(Qt::WindowStates(state));
+bool Viewer_ShowMinimized(void* ptr, uint64_t id) {
+    return qamelRunOnHandle<QamelView>(ptr, id, [&](QamelView *view) {
+        view->showMinimized();
+    });
 }
 
-void Viewer_ClearComponentCache(void* ptr) {
-    QamelView *view = static_cast<QamelView*>(ptr);
-    view->engine()->clearComponentCache();
+bool Viewer_ShowFullScreen(void* ptr, uint64_t id) {
+    return qamelRunOnHandle<QamelView>(ptr, id, [&](QamelView *view) {
+        view->showFullScreen();
+    });
+}
+
+bool Viewer_ShowNormal(void* ptr, uint64_t id) {
+    return qamelRunOnHandle<QamelView>(ptr, id, [&](QamelView *view) {
+        view->showNormal();
+    });
+}
+
+bool Viewer_SetWindowStates(void* ptr, uint64_t id, int state) {
+    return qamelRunOnHandle<QamelView>(ptr, id, [&](QamelView *view) {
+        view->setWindowStates(Qt::WindowStates(state));
+    });
+}
+
+int Viewer_Width(void* ptr, uint64_t id) {
+    int width = 0;
+    qamelRunOnHandle<QamelView>(ptr, id, [&](QamelView *view) { width = view->width(); });
+    return width;
+}
+
+int Viewer_Height(void* ptr, uint64_t id) {
+    int height = 0;
+    qamelRunOnHandle<QamelView>(ptr, id, [&](QamelView *view) { height = view->height(); });
+    return height;
+}
+
+void Viewer_Position(void* ptr, uint64_t id, int* x, int* y) {
+    *x = 0;
+    *y = 0;
+    qamelRunOnHandle<QamelView>(ptr, id, [&](QamelView *view) {
+        *x = view->x();
+        *y = view->y();
+    });
+}
+
+int Viewer_WindowStates(void* ptr, uint64_t id) {
+    int states = 0;
+    qamelRunOnHandle<QamelView>(ptr, id, [&](QamelView *view) { states = int(view->windowStates()); });
+    return states;
+}
+
+QamelScreen Viewer_Screen(void* ptr, uint64_t id) {
+    QamelScreen screen = {};
+    qamelRunOnHandle<QamelView>(ptr, id, [&](QamelView *view) { screen = qamelScreenInfo(view->screen()); });
+    return screen;
+}
+
+QamelImage Viewer_Grab(void* ptr, uint64_t id, bool* live) {
+    QamelImage image = qamelImage(QImage());
+    *live = qamelRunOnHandle<QamelView>(ptr, id, [&](QamelView *view) {
+        image = qamelImage(view->grabWindow());
+    });
+    return image;
+}
+
+// QamelGrabRequest keeps the result of item grab alive until it's ready or timed out,
+// then reports the image to Go and deletes itself. The grab is done in the next frame,
+// so the image is reported asynchronously instead of blocking the GUI thread.
+class QamelGrabRequest : public QObject {
+public:
+    QamelGrabRequest(QSharedPointer<QQuickItemGrabResult> result, uint64_t request, int timeout) :
+        QObject(), _result(result), _request(request) {
+        connect(result.data(), &QQuickItemGrabResult::ready, this, &QamelGrabRequest::finish);
+        QTimer::singleShot(timeout, this, &QamelGrabRequest::finish);
+    }
+
+private:
+    void finish() {
+        if (_finished) {
+            return;
+        }
+
+        _finished = true;
+        qamelItemGrabbed(_request, qamelImage(_result->image()));
+        deleteLater();
+    }
+
+    QSharedPointer<QQuickItemGrabResult> _result;
+    uint64_t _request;
+    bool _finished = false;
+};
+
+int Viewer_GrabItem(void* ptr, uint64_t id, char* objectName, uint64_t request, int timeout) {
+    QString name(objectName);
+
+    // The image is ready in the next frame, which never comes if GUI thread is blocked
+    if (qApp == nullptr || QThread::currentThread() == qApp->thread()) {
+        return -1;
+    }
+
+    int status = 0;
+    bool live = qamelRunOnHandle<QamelView>(ptr, id, [&](QamelView *view) {
+        QQuickItem *item = view->findItem(name);
+        if (item == nullptr) {
+            return;
+        }
+
+        status = 1;
+        QSharedPointer<QQuickItemGrabResult> result = item->grabToImage();
+        if (!result.isNull()) {
+            new QamelGrabRequest(result, request, timeout);
+            status = 2;
+        }
+    });
+
+    return live ? status : -2;
+}
+
+char* Viewer_Errors(void* ptr, uint64_t id, bool* live) {
+    char* errors = nullptr;
+    *live = qamelRunOnHandle<QamelView>(ptr, id, [&](QamelView *view) {
+        errors = qamelQmlErrors(view->errors());
+    });
+    return errors;
+}
+
+// itemNotFound returns error message for item that can't be found.
+static char* itemNotFound(const QString &objectName) {
+    return qamelCString(QString("item %1 not found").arg(objectName));
+}
+
+char* Viewer_ItemProperty(void* ptr, uint64_t id, char* objectName, char* property, char** errorMessage, bool* live) {
+    QString name(objectName);
+    char* result = nullptr;
+    *errorMessage = nullptr;
+
+    *live = qamelRunOnHandle<QamelView>(ptr, id, [&](QamelView *view) {
+        QQuickItem *item = view->findItem(name);
+        if (item == nullptr) {
+            *errorMessage = itemNotFound(name);
+            return;
+        }
+
+        QVariant value = item->property(property);
+        if (!value.isValid()) {
+            *errorMessage = qamelCString(QString("item %1 has no property %2").arg(name, property));
+            return;
+        }
+
+        if (value.userType() == qMetaTypeId<QJSValue>()) {
+            value = value.value<QJSValue>().toVariant();
+        }
+
+        // Wrap the value in array, since JSON document can't hold a single value
+        QJsonArray array;
+        array.append(QJsonValue::fromVariant(value));
+        result = qamelCString(QString(QJsonDocument(array).toJson(QJsonDocument::Compact)));
+    });
+
+    return result;
+}
+
+char* Viewer_SetItemProperty(void* ptr, uint64_t id, char* objectName, char* property, char* jsonValue, bool* live) {
+    QString name(objectName);
+    QByteArray json = "[" + QByteArray(jsonValue) + "]";
+    char* errorMessage = nullptr;
+
+    *live = qamelRunOnHandle<QamelView>(ptr, id, [&](QamelView *view) {
+        QQuickItem *item = view->findItem(name);
+        if (item == nullptr) {
+            errorMessage = itemNotFound(name);
+            return;
+        }
+
+        if (item->metaObject()->indexOfProperty(property) < 0) {
+            errorMessage = qamelCString(QString("item %1 has no property %2").arg(name, property));
+            return;
+        }
+
+        QVariant value = QJsonDocument::fromJson(json).array().at(0).toVariant();
+        if (!item->setProperty(property, value)) {
+            errorMessage = qamelCString(QString("failed to set property %1 of item %2").arg(property, name));
+        }
+    });
+
+    return errorMessage;
+}
+
+char* Viewer_FocusItem(void* ptr, uint64_t id, char* objectName, bool* live) {
+    QString name(objectName);
+    char* errorMessage = nullptr;
+
+    *live = qamelRunOnHandle<QamelView>(ptr, id, [&](QamelView *view) {
+        QQuickItem *item = view->findItem(name);
+        if (item == nullptr) {
+            errorMessage = itemNotFound(name);
+            return;
+        }
+
+        view->requestActivate();
+        item->forceActiveFocus();
+    });
+
+    return errorMessage;
+}
+
+char* Viewer_ClickItem(void* ptr, uint64_t id, char* objectName, bool* live) {
+    QString name(objectName);
+    char* errorMessage = nullptr;
+
+    *live = qamelRunOnHandle<QamelView>(ptr, id, [&](QamelView *view) {
+        QQuickItem *item = view->findItem(name);
+        if (item == nullptr) {
+            errorMessage = itemNotFound(name);
+            return;
+        }
+
+        // Press and release mouse in the center of the item
+        QPointF pos = item->mapToScene(QPointF(item->width() / 2, item->height() / 2));
+        QPointF globalPos = view->mapToGlobal(pos.toPoint());
+
+        QMouseEvent press(QEvent::MouseButtonPress, pos, pos, globalPos,
+            Qt::LeftButton, Qt::LeftButton, Qt::NoModifier);
+        QCoreApplication::sendEvent(view, &press);
+
+        QMouseEvent release(QEvent::MouseButtonRelease, pos, pos, globalPos,
+            Qt::LeftButton, Qt::NoButton, Qt::NoModifier);
+        QCoreApplication::sendEvent(view, &release);
+    });
+
+    return errorMessage;
+}
+
+bool Viewer_SendKey(void* ptr, uint64_t id, int key, int modifiers, char* text) {
+    QString keyText(text);
+    return qamelRunOnHandle<QamelView>(ptr, id, [&](QamelView *view) {
+        Qt::KeyboardModifiers mods = Qt::KeyboardModifiers(modifiers);
+        QKeyEvent press(QEvent::KeyPress, key, mods, keyText);
+        QCoreApplication::sendEvent(view, &press);
+
+        QKeyEvent release(QEvent::KeyRelease, key, mods, keyText);
+        QCoreApplication::sendEvent(view, &release);
+    });
+}
+
+bool Viewer_ClearComponentCache(void* ptr, uint64_t id) {
+    return qamelRunOnHandle<QamelView>(ptr, id, [&](QamelView *view) {
+        view->engine()->clearComponentCache();
+    });
+}
+
+bool Viewer_Reload(void* ptr, uint64_t id) {
+    // The reload is queued to the view, so it's dropped if the view deleted in the meantime
+    return qamelRunOnHandle<QamelView>(ptr, id, [&](QamelView *view) {
+        QMetaObject::invokeMethod(view, "reload", Qt::QueuedConnection);
+    });
+}
+
+bool Viewer_AddImportPath(void* ptr, uint64_t id, char* path) {
+    QString importPath(path);
+    return qamelRunOnHandle<QamelView>(ptr, id, [&](QamelView *view) {
+        view->engine()->addImportPath(importPath);
+    });
+}
+
+bool Viewer_SetImportPathList(void* ptr, uint64_t id, char** paths, int count) {
+    QStringList list = qamelStringList(paths, count);
+    return qamelRunOnHandle<QamelView>(ptr, id, [&](QamelView *view) {
+        view->engine()->setImportPathList(list);
+    });
+}
+
+char** Viewer_ImportPathList(void* ptr, uint64_t id, int* count) {
+    QStringList list;
+    qamelRunOnHandle<QamelView>(ptr, id, [&](QamelView *view) {
+        list = view->engine()->importPathList();
+    });
+    return qamelCStringArray(list, count);
+}
+
+bool Viewer_SetFileSelectors(void* ptr, uint64_t id, char** selectors, int count) {
+    QStringList list = qamelStringList(selectors, count);
+    return qamelRunOnHandle<QamelView>(ptr, id, [&](QamelView *view) {
+        qamelSetFileSelectors(view->engine(), list);
+    });
+}
+
+bool Viewer_AddPluginPath(void* ptr, uint64_t id, char* path) {
+    QString pluginPath(path);
+    return qamelRunOnHandle<QamelView>(ptr, id, [&](QamelView *view) {
+        view->engine()->addPluginPath(pluginPath);
+    });
+}
+
+bool Viewer_SetOfflineStoragePath(void* ptr, uint64_t id, char* path) {
+    QString storagePath(path);
+    return qamelRunOnHandle<QamelView>(ptr, id, [&](QamelView *view) {
+        view->engine()->setOfflineStoragePath(storagePath);
+    });
+}
+
+bool Viewer_SetBaseURL(void* ptr, uint64_t id, char* url) {
+    QUrl baseURL = QUrl(QString(url));
+    return qamelRunOnHandle<QamelView>(ptr, id, [&](QamelView *view) {
+        view->engine()->setBaseUrl(baseURL);
+    });
 }
 
-void Viewer_Reload(void* ptr) {
-    QMetaObject::invokeMethod(static_cast<QamelView*>(ptr), "reload");
+bool Viewer_PreserveProperties(void* ptr, uint64_t id, char* objectName, char** properties, int count) {
+    QString name(objectName);
+    QStringList list = qamelStringList(properties, count);
+    return qamelRunOnHandle<QamelView>(ptr, id, [&](QamelView *view) {
+        view->preserveProperties(name, list);
+    });
 }
 
 #include "moc-viewer.h"
\ No newline at end of file
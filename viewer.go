@@ -7,26 +7,24 @@ package qamel
 // #include "viewer.h"
 import "C"
 import (
+	"context"
 	"fmt"
-	"os"
-	fp "path/filepath"
-	"strings"
+	"image"
+	"sync"
 	"time"
 	"unsafe"
-
-	"github.com/fsnotify/fsnotify"
-	"github.com/sirupsen/logrus"
 )
 
 // Viewer is the QML viewer which wraps QQuickView
 type Viewer struct {
 	ptr unsafe.Pointer
+	id  uint64
 }
 
 // NewViewer constructs a QQuickView.
 func NewViewer() Viewer {
 	ptr := C.Viewer_NewViewer()
-	return Viewer{ptr: ptr}
+	return Viewer{ptr: ptr, id: registerHandle(ptr, viewerHandle)}
 }
 
 // NewViewerWithSource constructs a QQuickView with the given QML source.
@@ -36,17 +34,69 @@ func NewViewerWithSource(source string) Viewer {
 	return view
 }
 
-// SetSource sets the source to the url, loads the QML component and instantiates it.
-// The source could be a Qt resource path (qrc://icon) or a file path (file://path/to/icon).
-// However, it must be a valid path.
-func (view Viewer) SetSource(url string) {
-	if view.ptr == nil {
-		return
+// valid checks if the native view is still live.
+func (view Viewer) valid() bool {
+	return handleValid(view.ptr, view.id)
+}
+
+// err returns the error if the native view is not live anymore.
+func (view Viewer) err() error {
+	return handleError(view.ptr, view.id)
+}
+
+// Close closes the window, then destroys the view like Destroy.
+func (view Viewer) Close() error {
+	if !releaseHandle(view.ptr, view.id) {
+		return view.err()
 	}
 
+	C.Viewer_Close(view.ptr)
+	return nil
+}
+
+// Destroy deletes the native view in the GUI thread. After this, the view and all of
+// its copies become invalid: methods that return error will return ErrDestroyed and the
+// getters return zero values. The window callbacks and resource watchers of the view are released.
+func (view Viewer) Destroy() error {
+	if !releaseHandle(view.ptr, view.id) {
+		return view.err()
+	}
+
+	C.Viewer_Destroy(view.ptr)
+	return nil
+}
+
+// SetSource sets the source to the url, loads the QML component and instantiates it.
+// The source could be a Qt resource path (qrc://icon) or a file path (file://path/to/icon).
+// However, it must be a valid path. If the QML fails to load, the view shows an overlay
+// that lists the QML errors, which will be removed on the next successful load.
+func (view Viewer) SetSource(url string) error {
 	cURL := C.CString(url)
 	defer C.free(unsafe.Pointer(cURL))
-	C.Viewer_SetSource(view.ptr, cURL)
+	return nativeError(view.ptr, C.Viewer_SetSource(view.ptr, C.uint64_t(view.id), cURL))
+}
+
+// SetSourceData loads the QML component from data and instantiates it. The baseURL is used
+// as the URL of the component, so relative imports and URLs inside the QML will be resolved
+// against it. If data can't be compiled or instantiated, the returned error contains the
+// list of QML errors. Since the component is created immediately, data must not import
+// remote modules, which are loaded asynchronously.
+func (view Viewer) SetSourceData(data []byte, baseURL string) error {
+	cData := C.CBytes(data)
+	cBaseURL := C.CString(baseURL)
+	defer func() {
+		C.free(cData)
+		C.free(unsafe.Pointer(cBaseURL))
+	}()
+
+	var live C.bool
+	cErrors := C.Viewer_SetSourceData(view.ptr, C.uint64_t(view.id),
+		(*C.char)(cData), C.int(int32(len(data))), cBaseURL, &live)
+	if err := nativeError(view.ptr, live); err != nil {
+		return err
+	}
+
+	return qmlError(cErrors)
 }
 
 // SetResizeMode sets whether the view should resize the window contents.
@@ -54,78 +104,46 @@ func (view Viewer) SetSource(url string) {
 // to the size of the root item in the QML. If this property is set to
 // SizeRootObjectToView, the view will automatically resize the root item to the
 // size of the view.
-func (view Viewer) SetResizeMode(resizeMode ResizeMode) {
-	if view.ptr == nil {
-		return
-	}
-
-	C.Viewer_SetResizeMode(view.ptr, C.int(resizeMode))
+func (view Viewer) SetResizeMode(resizeMode ResizeMode) error {
+	return nativeError(view.ptr, C.Viewer_SetResizeMode(view.ptr, C.uint64_t(view.id), C.int(resizeMode)))
 }
 
 // SetFlags sets the flags of the window. The window flags control the window's appearance
 // in the windowing system, whether it's a dialog, popup, or a regular window, and whether it
 // should have a title bar, etc. The actual window flags might differ from the flags set with
 // setFlags() if the requested flags could not be fulfilled.
-func (view Viewer) SetFlags(flags WindowFlags) {
-	if view.ptr == nil {
-		return
-	}
-
-	C.Viewer_SetFlags(view.ptr, C.int(flags))
+func (view Viewer) SetFlags(flags WindowFlags) error {
+	return nativeError(view.ptr, C.Viewer_SetFlags(view.ptr, C.uint64_t(view.id), C.int(flags)))
 }
 
 // SetHeight sets the height of the window.
-func (view Viewer) SetHeight(height int) {
-	if view.ptr == nil {
-		return
-	}
-
-	C.Viewer_SetHeight(view.ptr, C.int(int32(height)))
+func (view Viewer) SetHeight(height int) error {
+	return nativeError(view.ptr, C.Viewer_SetHeight(view.ptr, C.uint64_t(view.id), C.int(int32(height))))
 }
 
 // SetWidth sets the width of the window.
-func (view Viewer) SetWidth(width int) {
-	if view.ptr == nil {
-		return
-	}
-
-	C.Viewer_SetWidth(view.ptr, C.int(int32(width)))
+func (view Viewer) SetWidth(width int) error {
+	return nativeError(view.ptr, C.Viewer_SetWidth(view.ptr, C.uint64_t(view.id), C.int(int32(width))))
 }
 
 // SetMaximumHeight sets the maximum height of the window.
-func (view Viewer) SetMaximumHeight(height int) {
-	if view.ptr == nil {
-		return
-	}
-
-	C.Viewer_SetMaximumHeight(view.ptr, C.int(int32(height)))
+func (view Viewer) SetMaximumHeight(height int) error {
+	return nativeError(view.ptr, C.Viewer_SetMaximumHeight(view.ptr, C.uint64_t(view.id), C.int(int32(height))))
 }
 
 // SetMaximumWidth sets the maximum width of the window.
-func (view Viewer) SetMaximumWidth(width int) {
-	if view.ptr == nil {
-		return
-	}
-
-	C.Viewer_SetMaximumWidth(view.ptr, C.int(int32(width)))
+func (view Viewer) SetMaximumWidth(width int) error {
+	return nativeError(view.ptr, C.Viewer_SetMaximumWidth(view.ptr, C.uint64_t(view.id), C.int(int32(width))))
 }
 
 // SetMinimumHeight sets the minimum height of the window.
-func (view Viewer) SetMinimumHeight(height int) {
-	if view.ptr == nil {
-		return
-	}
-
-	C.Viewer_SetMinimumHeight(view.ptr, C.int(int32(height)))
+func (view Viewer) SetMinimumHeight(height int) error {
+	return nativeError(view.ptr, C.Viewer_SetMinimumHeight(view.ptr, C.uint64_t(view.id), C.int(int32(height))))
 }
 
 // SetMinimumWidth sets the minimum width of the window.
-func (view Viewer) SetMinimumWidth(width int) {
-	if view.ptr == nil {
-		return
-	}
-
-	C.Viewer_SetMinimumWidth(view.ptr, C.int(int32(width)))
+func (view Viewer) SetMinimumWidth(width int) error {
+	return nativeError(view.ptr, C.Viewer_SetMinimumWidth(view.ptr, C.uint64_t(view.id), C.int(int32(width))))
 }
 
 // SetOpacity sets the opacity of the window in the windowing system. If the windowing system supports
@@ -133,109 +151,69 @@ func (view Viewer) SetMinimumWidth(width int) {
 // A value of 1.0 or above is treated as fully opaque, whereas a value of 0.0 or below is treated as
 // fully transparent. Values inbetween represent varying levels of translucency between the two extremes.
 // The default value is 1.0.
-func (view Viewer) SetOpacity(opacity float64) {
-	if view.ptr == nil {
-		return
-	}
-
-	C.Viewer_SetOpacity(view.ptr, C.double(opacity))
+func (view Viewer) SetOpacity(opacity float64) error {
+	return nativeError(view.ptr, C.Viewer_SetOpacity(view.ptr, C.uint64_t(view.id), C.double(opacity)))
 }
 
 // SetTitle sets the window's title in the windowing system. The window title might appear in the title
 // area of the window decorations, depending on the windowing system and the window flags. It might also
 // be used by the windowing system to identify the window in other contexts, such as in the task switcher.
-func (view Viewer) SetTitle(title string) {
-	if view.ptr == nil {
-		return
-	}
-
+func (view Viewer) SetTitle(title string) error {
 	cTitle := C.CString(title)
 	defer C.free(unsafe.Pointer(cTitle))
-	C.Viewer_SetTitle(view.ptr, cTitle)
+	return nativeError(view.ptr, C.Viewer_SetTitle(view.ptr, C.uint64_t(view.id), cTitle))
 }
 
 // SetVisible sets whether the window is visible or not. This property controls the visibility of the
 // window in the windowing system. By default, the window is not visible, you must call setVisible(true),
 // or show() or similar to make it visible.
-func (view Viewer) SetVisible(visible bool) {
-	if view.ptr == nil {
-		return
-	}
-
-	C.Viewer_SetVisible(view.ptr, C.bool(visible))
+func (view Viewer) SetVisible(visible bool) error {
+	return nativeError(view.ptr, C.Viewer_SetVisible(view.ptr, C.uint64_t(view.id), C.bool(visible)))
 }
 
 // SetPosition sets the position of the window on the desktop to x, y.
-func (view Viewer) SetPosition(x int, y int) {
-	if view.ptr == nil {
-		return
-	}
-
-	C.Viewer_SetPosition(view.ptr, C.int(int32(x)), C.int(int32(y)))
+func (view Viewer) SetPosition(x int, y int) error {
+	return nativeError(view.ptr, C.Viewer_SetPosition(view.ptr, C.uint64_t(view.id), C.int(int32(x)), C.int(int32(y))))
 }
 
 // SetIcon sets the window's icon in the windowing system. The window icon might be used by the windowing
 // system for example to decorate the window, and/or in the task switcher.
 // Note: On macOS, the window title bar icon is meant for windows representing documents, and will only
 // show up if a file path is also set.
-func (view Viewer) SetIcon(fileName string) {
-	if view.ptr == nil {
-		return
-	}
-
+func (view Viewer) SetIcon(fileName string) error {
 	cFileName := C.CString(fileName)
 	defer C.free(unsafe.Pointer(cFileName))
-	C.Viewer_SetIcon(view.ptr, cFileName)
+	return nativeError(view.ptr, C.Viewer_SetIcon(view.ptr, C.uint64_t(view.id), cFileName))
 }
 
 // Show shows the window. This is equivalent to calling showFullScreen(), showMaximized(), or
 // showNormal(), depending on the platform's default behavior for the window type and flags.
-func (view Viewer) Show() {
-	if view.ptr == nil {
-		return
-	}
-
-	C.Viewer_Show(view.ptr)
+func (view Viewer) Show() error {
+	return nativeError(view.ptr, C.Viewer_Show(view.ptr, C.uint64_t(view.id)))
 }
 
 // ShowMaximized shows the window as maximized.
 // Equivalent to calling setWindowStates(WindowMaximized) and then setVisible(true).
-func (view Viewer) ShowMaximized() {
-	if view.ptr == nil {
-		return
-	}
-
-	C.Viewer_ShowMaximized(view.ptr)
+func (view Viewer) ShowMaximized() error {
+	return nativeError(view.ptr, C.Viewer_ShowMaximized(view.ptr, C.uint64_t(view.id)))
 }
 
 // ShowMinimized shows the window as minimized.
 // Equivalent to calling setWindowStates(WindowMinimized) and then setVisible(true).
-func (view Viewer) ShowMinimized() {
-	if view.ptr == nil {
-		return
-	}
-
-	C.Viewer_ShowMinimized(view.ptr)
+func (view Viewer) ShowMinimized() error {
+	return nativeError(view.ptr, C.Viewer_ShowMinimized(view.ptr, C.uint64_t(view.id)))
 }
 
 // ShowFullScreen shows the window as fullscreen.
 // Equivalent to calling setWindowStates(WindowFullScreen) and then setVisible(true).
-func (view Viewer) ShowFullScreen() {
-	if view.ptr == nil {
-		return
-	}
-
-	C.Viewer_ShowFullScreen(view.ptr)
+func (view Viewer) ShowFullScreen() error {
+	return nativeError(view.ptr, C.Viewer_ShowFullScreen(view.ptr, C.uint64_t(view.id)))
 }
 
 // ShowNormal shows the window as normal, i.e. neither maximized, minimized, nor fullscreen.
 // Equivalent to calling setWindowStates(WindowNoState) and then setVisible(true).
-func (view Viewer) ShowNormal() {
-	if view.ptr == nil {
-		return
-	}
-
-	C.Viewer_ShowNormal(view.ptr)
+func (view Viewer) ShowNormal() error {
+	return nativeError(view.ptr, C.Viewer_ShowNormal(view.ptr, C.uint64_t(view.id)))
 }
 
 // SetWindowStates sets the screen-occupation state of the window. The window state represents whether
@@ -243,12 +221,134 @@ func (view Viewer) ShowNormal() {
 // be in a combination of several states. For example, if the window is both minimized and maximized,
 // the window will appear minimized, but clicking on the task bar entry will restore it to the
 // maximized state.
-func (view Viewer) SetWindowStates(state WindowStates) {
-	if view.ptr == nil {
-		return
+func (view Viewer) SetWindowStates(state WindowStates) error {
+	return nativeError(view.ptr, C.Viewer_SetWindowStates(view.ptr, C.uint64_t(view.id), C.int(state)))
+}
+
+// Width returns the width of the window, excluding any window frame.
+func (view Viewer) Width() int {
+	return int(C.Viewer_Width(view.ptr, C.uint64_t(view.id)))
+}
+
+// Height returns the height of the window, excluding any window frame.
+func (view Viewer) Height() int {
+	return int(C.Viewer_Height(view.ptr, C.uint64_t(view.id)))
+}
+
+// Position returns the position of the window on the desktop, excluding any window frame.
+func (view Viewer) Position() (x int, y int) {
+	var cX, cY C.int
+	C.Viewer_Position(view.ptr, C.uint64_t(view.id), &cX, &cY)
+	return int(cX), int(cY)
+}
+
+// WindowStates returns the current screen-occupation state of the window.
+func (view Viewer) WindowStates() WindowStates {
+	return WindowStates(C.Viewer_WindowStates(view.ptr, C.uint64_t(view.id)))
+}
+
+// Screen returns the screen on which the window is displayed.
+func (view Viewer) Screen() Screen {
+	return goScreen(C.Viewer_Screen(view.ptr, C.uint64_t(view.id)))
+}
+
+// SaveGeometry saves the current geometry and state of the window into bytes, which can be
+// stored and used later by RestoreGeometry to restore the window placement. For maximized
+// or full screen window, its normal geometry is saved instead.
+func (view Viewer) SaveGeometry() []byte {
+	return saveGeometry(view.ptr, view.id)
+}
+
+// RestoreGeometry restores the geometry and state of the window that saved by SaveGeometry.
+// The window will be moved and resized if necessary, so it fits inside the available screens.
+// Minimized state is never restored.
+func (view Viewer) RestoreGeometry(data []byte) error {
+	return restoreGeometry(view, data)
+}
+
+// Grab renders the content of the window and returns it as image. It works even when
+// the window is not visible, e.g. when running with QT_QPA_PLATFORM=offscreen.
+func (view Viewer) Grab() (image.Image, error) {
+	var live C.bool
+	cImg := C.Viewer_Grab(view.ptr, C.uint64_t(view.id), &live)
+	if err := nativeError(view.ptr, live); err != nil {
+		return nil, err
+	}
+
+	img := goImage(cImg)
+	if img == nil {
+		return nil, fmt.Errorf("failed to grab window")
+	}
+
+	return img, nil
+}
+
+// grabItemTimeout is the maximum time to wait for the item to be rendered by GrabItem.
+const grabItemTimeout = 5 * time.Second
+
+var (
+	grabMutex      = sync.Mutex{}
+	grabLastID     = uint64(0)
+	mapGrabRequest = map[uint64]chan *image.NRGBA{}
+)
+
+// GrabItem renders the QML item with the specified objectName and returns it as image.
+// The item must be visible and its window must be exposed, since the rendering is done
+// in the next frame of the window. The result is waited in the calling goroutine, so it
+// must not be called from the GUI thread, e.g. inside a callback or RunOnGuiThread.
+func (view Viewer) GrabItem(objectName string) (image.Image, error) {
+	cObjectName := C.CString(objectName)
+	defer C.free(unsafe.Pointer(cObjectName))
+
+	// Register the request, so the image can be received once it's ready
+	result := make(chan *image.NRGBA, 1)
+
+	grabMutex.Lock()
+	grabLastID++
+	request := grabLastID
+	mapGrabRequest[request] = result
+	grabMutex.Unlock()
+
+	defer func() {
+		grabMutex.Lock()
+		delete(mapGrabRequest, request)
+		grabMutex.Unlock()
+	}()
+
+	timeout := C.int(grabItemTimeout / time.Millisecond)
+	switch C.Viewer_GrabItem(view.ptr, C.uint64_t(view.id), cObjectName, C.uint64_t(request), timeout) {
+	case -2:
+		return nil, nativeError(view.ptr, false)
+	case -1:
+		return nil, fmt.Errorf("can't grab item %q from the GUI thread", objectName)
+	case 0:
+		return nil, fmt.Errorf("item %q not found", objectName)
+	case 1:
+		return nil, fmt.Errorf("failed to grab item %q", objectName)
+	}
+
+	select {
+	case img := <-result:
+		if img == nil {
+			return nil, fmt.Errorf("failed to grab item %q", objectName)
+		}
+		return img, nil
+	case <-time.After(grabItemTimeout + time.Second):
+		return nil, fmt.Errorf("timeout while grabbing item %q", objectName)
 	}
+}
+
+//export qamelItemGrabbed
+func qamelItemGrabbed(request C.uint64_t, cImg C.QamelImage) {
+	img := goImage(cImg)
 
-	C.Viewer_SetWindowStates(view.ptr, C.int(state))
+	grabMutex.Lock()
+	result, ok := mapGrabRequest[uint64(request)]
+	grabMutex.Unlock()
+
+	if ok {
+		result <- img
+	}
 }
 
 // ClearComponentCache clears the engine's internal component cache. This function causes the property
@@ -258,103 +358,116 @@ func (view Viewer) SetWindowStates(state WindowStates) {
 // component data. This may be useful in order to reload a smaller subset of the previous component set,
 // or to load a new version of a previously loaded component. Once the component cache has been cleared,
 // components must be loaded before any new objects can be created.
-func (view Viewer) ClearComponentCache() {
-	if view.ptr == nil {
-		return
-	}
+func (view Viewer) ClearComponentCache() error {
+	return nativeError(view.ptr, C.Viewer_ClearComponentCache(view.ptr, C.uint64_t(view.id)))
+}
 
-	C.Viewer_ClearComponentCache(view.ptr)
+// Reload reloads the active QML view. The reload is queued and
+// done in the GUI thread, so it's safe to call from any goroutine.
+func (view Viewer) Reload() error {
+	return nativeError(view.ptr, C.Viewer_Reload(view.ptr, C.uint64_t(view.id)))
 }
 
-// Reload reloads the active QML view.
-func (view Viewer) Reload() {
-	if view.ptr == nil {
-		return
-	}
+// AddImportPath adds path as a directory where the view's engine searches for installed
+// modules in a URL-based directory structure. The path may be a local filesystem directory,
+// a Qt Resource path (:/imports), a Qt Resource url (qrc:/imports) or a URL.
+func (view Viewer) AddImportPath(path string) error {
+	cPath := C.CString(path)
+	defer C.free(unsafe.Pointer(cPath))
+	return nativeError(view.ptr, C.Viewer_AddImportPath(view.ptr, C.uint64_t(view.id), cPath))
+}
 
-	C.Viewer_Reload(view.ptr)
+// SetImportPathList sets paths as the list of directories where the view's engine searches
+// for installed modules in a URL-based directory structure.
+func (view Viewer) SetImportPathList(paths []string) error {
+	cPaths, free := cStringArray(paths)
+	defer free()
+	return nativeError(view.ptr, C.Viewer_SetImportPathList(view.ptr, C.uint64_t(view.id), cPaths, C.int(int32(len(paths)))))
 }
 
-// WatchResourceDir watches for change inside the specified resource dir.
-// When change happened, the view will be reloaded immediately.
-// The directory path must be absolute.
-// Only use this in development environment.
-func (view Viewer) WatchResourceDir(dirPath string) {
-	// Make sure directory is exists
-	dirInfo, err := os.Stat(dirPath)
-	if os.IsNotExist(err) || !dirInfo.IsDir() {
-		logrus.Fatalf("directory %s does not exist\n", dirPath)
-	}
+// ImportPathList returns the list of directories where the view's engine searches for
+// installed modules in a URL-based directory structure.
+func (view Viewer) ImportPathList() []string {
+	var count C.int
+	cPaths := C.Viewer_ImportPathList(view.ptr, C.uint64_t(view.id), &count)
+	return goStringSlice(cPaths, count)
+}
 
-	// Make sure directory path is absolute
-	if !fp.IsAbs(dirPath) {
-		logrus.Fatalln("path to directory must be absolute")
-	}
+// SetFileSelectors sets the extra selectors that used to choose the variant of QML files.
+// For example, with selector "kiosk", the file +kiosk/Main.qml will be loaded instead of
+// Main.qml if it exists. Selectors are matched in order, and the leading "+" is optional.
+// It must be called before setting the source, e.g. at startup from the app config.
+func (view Viewer) SetFileSelectors(selectors []string) error {
+	selectors = cleanFileSelectors(selectors)
+	cSelectors, free := cStringArray(selectors)
+	defer free()
+	return nativeError(view.ptr, C.Viewer_SetFileSelectors(view.ptr, C.uint64_t(view.id), cSelectors, C.int(int32(len(selectors)))))
+}
 
-	// Create watcher
-	watcher, err := fsnotify.NewWatcher()
-	if err != nil {
-		logrus.Fatalln("failed to create watcher:", err)
-	}
-	defer watcher.Close()
+// AddPluginPath adds path as a directory where the view's engine searches for native
+// plugins for imported modules (referenced in the qmldir file).
+func (view Viewer) AddPluginPath(path string) error {
+	cPath := C.CString(path)
+	defer C.free(unsafe.Pointer(cPath))
+	return nativeError(view.ptr, C.Viewer_AddPluginPath(view.ptr, C.uint64_t(view.id), cPath))
+}
 
-	// Add all subdir inside resource dir to watcher
-	err = fp.Walk(dirPath, func(path string, info os.FileInfo, _ error) error {
-		if info.IsDir() {
-			return watcher.Add(path)
-		}
-		return nil
-	})
+// SetOfflineStoragePath sets the directory for storing offline user data. This is where
+// the data from QML's LocalStorage is saved.
+func (view Viewer) SetOfflineStoragePath(path string) error {
+	cPath := C.CString(path)
+	defer C.free(unsafe.Pointer(cPath))
+	return nativeError(view.ptr, C.Viewer_SetOfflineStoragePath(view.ptr, C.uint64_t(view.id), cPath))
+}
 
-	if err != nil {
-		logrus.Fatalln("failed to scan resource dir:", err)
+// SetBaseURL sets the base URL for the view's engine. The base URL is only used to resolve
+// components when a relative URL is passed to the QQmlComponent constructor. If a base
+// URL is not specified, the current working directory is used.
+func (view Viewer) SetBaseURL(url string) error {
+	cURL := C.CString(url)
+	defer C.free(unsafe.Pointer(cURL))
+	return nativeError(view.ptr, C.Viewer_SetBaseURL(view.ptr, C.uint64_t(view.id), cURL))
+}
+
+// PreserveProperties marks properties of the QML object with the specified objectName to be
+// preserved across reload. Before reload, the value of those properties are saved, then
+// restored to the object with the same objectName once the QML is loaded again. This is
+// useful to keep navigation state, text input and scroll position during live reload.
+// Calling it without properties removes the mark for that object. The properties can
+// also be marked from QML by declaring `property var qamelPreserve: ["text", "contentY"]`
+// in an object that has objectName. The object is matched by its path, i.e. the objectName
+// of itself and its named ancestors, so objects that share the same path are skipped.
+// Window geometry and state are always preserved.
+func (view Viewer) PreserveProperties(objectName string, properties ...string) error {
+	cObjectName := C.CString(objectName)
+	cProperties, free := cStringArray(properties)
+	defer func() {
+		C.free(unsafe.Pointer(cObjectName))
+		free()
+	}()
+
+	return nativeError(view.ptr, C.Viewer_PreserveProperties(view.ptr, C.uint64_t(view.id), cObjectName, cProperties, C.int(int32(len(properties)))))
+}
+
+// WatchResourceDir watches for change inside the specified resource dir and its subdirs,
+// including the ones created later. When change happened, the view will be reloaded in
+// the GUI thread. The directory path must be absolute. WatchResourceDir blocks until ctx
+// is cancelled, so usually it's run in its own goroutine. It returns error if the watcher
+// can't be started. Only use this in development environment.
+func (view Viewer) WatchResourceDir(ctx context.Context, dirPath string, opts WatchOptions) error {
+	if err := view.err(); err != nil {
+		return err
 	}
 
-	// Watch for files change
-	logrus.Infoln("File watcher enabled for", dirPath)
-	logrus.Infoln("Only use it in safe environment")
-
-	lastEvent := struct {
-		Name string
-		Time time.Time
-	}{}
-
-	for {
-		select {
-		case event := <-watcher.Events:
-			// Make sure the file is not qmlc or jsc
-			fileName := event.Name
-			if fp.Ext(fileName) == ".qmlc" || strings.Contains(fileName, ".qmlc.") ||
-				fp.Ext(fileName) == ".jsc" || strings.Contains(fileName, ".jsc.") {
-				continue
-			}
-
-			// In some OS, the write events fired twice.
-			// To fix this, check if current event is happened less than one sec before.
-			// If yes, skip this event.
-			now := time.Now()
-			eventName := fmt.Sprintf("%s: %s", event.Op.String(), fileName)
-			if lastEvent.Name == eventName && now.Sub(lastEvent.Time).Seconds() <= 1.0 {
-				continue
-			}
-
-			// Also make sure that file is not empty
-			if info, err := os.Stat(fileName); err != nil || info.Size() == 0 {
-				continue
-			}
-
-			// Else, save this event and reload view.
-			lastEvent = struct {
-				Name string
-				Time time.Time
-			}{Name: eventName, Time: now}
-
-			logrus.Println(eventName)
-			view.Reload()
-		case err := <-watcher.Errors:
-			if err != nil {
-				logrus.Errorln("Watcher error:", err)
-			}
-		}
+	// Stop watching when the view destroyed
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	removeCleanup, ok := addHandleCleanup(view.ptr, view.id, cancel)
+	if !ok {
+		return ErrDestroyed
 	}
+	defer removeCleanup()
+
+	return watchResourceDir(ctx, dirPath, opts, func() { view.Reload() })
 }
@@ -5,6 +5,8 @@
 
 #include <stdint.h>
 #include <stdbool.h>
+#include "screen.h"
+#include "image.h"
 
 #ifdef __cplusplus
 
@@ -17,29 +19,55 @@ extern "C" {
 // Constructor
 void* Viewer_NewViewer();
 
+// Destructors
+void Viewer_Close(void* ptr);
+void Viewer_Destroy(void* ptr);
+
 // Methods
-void Viewer_SetSource(void* ptr, char* url);
-void Viewer_SetResizeMode(void* ptr, int resizeMode);
-void Viewer_SetFlags(void* ptr, int flags);
-void Viewer_SetHeight(void* ptr, int height);
-void Viewer_SetWidth(void* ptr, int width);
-void Viewer_SetMaximumHeight(void* ptr, int height);
-void Viewer_SetMaximumWidth(void* ptr, int width);
-void Viewer_SetMinimumHeight(void* ptr, int height);
-void Viewer_SetMinimumWidth(void* ptr, int width);
-void Viewer_SetOpacity(void* ptr, double opacity);
-void Viewer_SetTitle(void* ptr, char* title);
-void Viewer_SetVisible(void* ptr, bool visible);
-void Viewer_SetPosition(void* ptr, int x, int y);
-void Viewer_SetIcon(void* ptr, char* fileName);
-void Viewer_Show(void* ptr);
-void Viewer_ShowMaximized(void* ptr);
-void Viewer_ShowMinimized(void* ptr);
-void Viewer_ShowFullScreen(void* ptr);
-void Viewer_ShowNormal(void* ptr);
-void Viewer_SetWindowStates(void* ptr, int state);
-void Viewer_ClearComponentCache(void* ptr);
-void Viewer_Reload(void* ptr);
+bool Viewer_SetSource(void* ptr, uint64_t id, char* url);
+char* Viewer_SetSourceData(void* ptr, uint64_t id, char* data, int length, char* baseURL, bool* live);
+bool Viewer_SetResizeMode(void* ptr, uint64_t id, int resizeMode);
+bool Viewer_SetFlags(void* ptr, uint64_t id, int flags);
+bool Viewer_SetHeight(void* ptr, uint64_t id, int height);
+bool Viewer_SetWidth(void* ptr, uint64_t id, int width);
+bool Viewer_SetMaximumHeight(void* ptr, uint64_t id, int height);
+bool Viewer_SetMaximumWidth(void* ptr, uint64_t id, int width);
+bool Viewer_SetMinimumHeight(void* ptr, uint64_t id, int height);
+bool Viewer_SetMinimumWidth(void* ptr, uint64_t id, int width);
+bool Viewer_SetOpacity(void* ptr, uint64_t id, double opacity);
+bool Viewer_SetTitle(void* ptr, uint64_t id, char* title);
+bool Viewer_SetVisible(void* ptr, uint64_t id, bool visible);
+bool Viewer_SetPosition(void* ptr, uint64_t id, int x, int y);
+bool Viewer_SetIcon(void* ptr, uint64_t id, char* fileName);
+bool Viewer_Show(void* ptr, uint64_t id);
+bool Viewer_ShowMaximized(void* ptr, uint64_t id);
+bool Viewer_ShowMinimized(void* ptr, uint64_t id);
+bool Viewer_ShowFullScreen(void* ptr, uint64_t id);
+bool Viewer_ShowNormal(void* ptr, uint64_t id);
+bool Viewer_SetWindowStates(void* ptr, uint64_t id, int state);
+int Viewer_Width(void* ptr, uint64_t id);
+int Viewer_Height(void* ptr, uint64_t id);
+void Viewer_Position(void* ptr, uint64_t id, int* x, int* y);
+int Viewer_WindowStates(void* ptr, uint64_t id);
+QamelScreen Viewer_Screen(void* ptr, uint64_t id);
+QamelImage Viewer_Grab(void* ptr, uint64_t id, bool* live);
+int Viewer_GrabItem(void* ptr, uint64_t id, char* objectName, uint64_t request, int timeout);
+char* Viewer_Errors(void* ptr, uint64_t id, bool* live);
+char* Viewer_ItemProperty(void* ptr, uint64_t id, char* objectName, char* property, char** errorMessage, bool* live);
+char* Viewer_SetItemProperty(void* ptr, uint64_t id, char* objectName, char* property, char* jsonValue, bool* live);
+char* Viewer_FocusItem(void* ptr, uint64_t id, char* objectName, bool* live);
+char* Viewer_ClickItem(void* ptr, uint64_t id, char* objectName, bool* live);
+bool Viewer_SendKey(void* ptr, uint64_t id, int key, int modifiers, char* text);
+bool Viewer_ClearComponentCache(void* ptr, uint64_t id);
+bool Viewer_Reload(void* ptr, uint64_t id);
+bool Viewer_PreserveProperties(void* ptr, uint64_t id, char* objectName, char** properties, int count);
+bool Viewer_AddImportPath(void* ptr, uint64_t id, char* path);
+bool Viewer_SetImportPathList(void* ptr, uint64_t id, char** paths, int count);
+char** Viewer_ImportPathList(void* ptr, uint64_t id, int* count);
+bool Viewer_SetFileSelectors(void* ptr, uint64_t id, char** selectors, int count);
+bool Viewer_AddPluginPath(void* ptr, uint64_t id, char* path);
+bool Viewer_SetOfflineStoragePath(void* ptr, uint64_t id, char* path);
+bool Viewer_SetBaseURL(void* ptr, uint64_t id, char* url);
 
 #ifdef __cplusplus
 }
@@ -0,0 +1,240 @@
+package qamel
+
+import (
+	"context"
+	"fmt"
+	"os"
+	fp "path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultWatchDebounce is the debounce duration that used by
+// WatchResourceDir when WatchOptions.Debounce is not specified.
+const DefaultWatchDebounce = 500 * time.Millisecond
+
+// WatchOptions is the options for watching resource directory.
+type WatchOptions struct {
+	// Debounce is the duration to wait after the last file change before
+	// reloading the view. This is useful since some editors and OS fire
+	// several events for a single save. Default is DefaultWatchDebounce.
+	Debounce time.Duration
+
+	// Include is list of glob patterns for files that will trigger reload.
+	// The pattern is matched against the file name and the file path relative
+	// to the watched directory, using the syntax of filepath.Match.
+	// If empty, all files will trigger reload.
+	Include []string
+
+	// Exclude is list of glob patterns for files that will be ignored, even
+	// when they match Include. Compiled QML cache (*.qmlc and *.jsc) are
+	// always ignored.
+	Exclude []string
+}
+
+// matchAny checks if the file matches one of the patterns. It returns error
+// if one of the patterns is malformed.
+func matchAny(patterns []string, baseName, relPath string) (bool, error) {
+	for _, pattern := range patterns {
+		matchName, err := fp.Match(pattern, baseName)
+		if err != nil {
+			return false, err
+		}
+
+		matchPath, err := fp.Match(pattern, relPath)
+		if err != nil {
+			return false, err
+		}
+
+		if matchName || matchPath {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// accept checks if change in the specified file should trigger reload.
+func (opts WatchOptions) accept(dirPath, filePath string) bool {
+	// Make sure the file is not qmlc or jsc
+	baseName := fp.Base(filePath)
+	if fp.Ext(baseName) == ".qmlc" || strings.Contains(baseName, ".qmlc.") ||
+		fp.Ext(baseName) == ".jsc" || strings.Contains(baseName, ".jsc.") {
+		return false
+	}
+
+	relPath, err := fp.Rel(dirPath, filePath)
+	if err != nil {
+		relPath = baseName
+	}
+	relPath = fp.ToSlash(relPath)
+
+	if excluded, _ := matchAny(opts.Exclude, baseName, relPath); excluded {
+		return false
+	}
+
+	if len(opts.Include) == 0 {
+		return true
+	}
+
+	included, _ := matchAny(opts.Include, baseName, relPath)
+	return included
+}
+
+// validate checks if all glob patterns in options are well-formed.
+func (opts WatchOptions) validate() error {
+	patterns := append([]string{}, opts.Include...)
+	patterns = append(patterns, opts.Exclude...)
+	for _, pattern := range patterns {
+		if _, err := fp.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid pattern %q: %v", pattern, err)
+		}
+	}
+
+	if opts.Debounce < 0 {
+		return fmt.Errorf("debounce must not be negative")
+	}
+
+	return nil
+}
+
+// watchResourceDir watches for change inside the specified resource dir.
+// When change happened, the reload function will be called once the changes
+// settled down. It blocks until ctx is cancelled or the watcher failed.
+func watchResourceDir(ctx context.Context, dirPath string, opts WatchOptions, reload func()) error {
+	// Make sure directory path is absolute
+	if !fp.IsAbs(dirPath) {
+		return fmt.Errorf("path to directory must be absolute")
+	}
+
+	// Make sure directory is exists
+	dirInfo, err := os.Stat(dirPath)
+	if err != nil || !dirInfo.IsDir() {
+		return fmt.Errorf("directory %s does not exist", dirPath)
+	}
+
+	// Check options
+	if err = opts.validate(); err != nil {
+		return err
+	}
+
+	debounce := opts.Debounce
+	if debounce == 0 {
+		debounce = DefaultWatchDebounce
+	}
+
+	// Create watcher
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create watcher: %v", err)
+	}
+	defer watcher.Close()
+
+	// Add all subdir inside resource dir to watcher
+	if err = watchSubDirs(watcher, dirPath); err != nil {
+		return fmt.Errorf("failed to scan resource dir: %v", err)
+	}
+
+	// Watch for files change
+	logrus.Infoln("File watcher enabled for", dirPath)
+	logrus.Infoln("Only use it in safe environment")
+
+	timer := time.NewTimer(debounce)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+
+			// If a new directory created, watch it as well
+			fileName := event.Name
+			info, err := os.Stat(fileName)
+			if err == nil && info.IsDir() {
+				if event.Op&fsnotify.Create != fsnotify.Create {
+					continue
+				}
+
+				if err := watchSubDirs(watcher, fileName); err != nil {
+					logrus.Errorln("Failed to watch new dir:", err)
+				}
+
+				// Files that created together with the dir (e.g. when it's copied or
+				// moved into resource dir) don't fire their own events, so check it here.
+				if containsAcceptedFile(opts, dirPath, fileName) {
+					logrus.Printf("%s: %s\n", event.Op.String(), fileName)
+					timer.Reset(debounce)
+				}
+				continue
+			}
+
+			// Make sure that file is not empty
+			if err == nil && info.Size() == 0 {
+				continue
+			}
+
+			if !opts.accept(dirPath, fileName) {
+				continue
+			}
+
+			// In some OS, the write events fired several times.
+			// To fix this, reload only after the events settled down.
+			logrus.Printf("%s: %s\n", event.Op.String(), fileName)
+			timer.Reset(debounce)
+
+		case <-timer.C:
+			reload()
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+
+			if err != nil {
+				logrus.Errorln("Watcher error:", err)
+			}
+		}
+	}
+}
+
+// watchSubDirs adds the dir and all of its subdir to the watcher.
+func watchSubDirs(watcher *fsnotify.Watcher, dirPath string) error {
+	return fp.Walk(dirPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			return watcher.Add(path)
+		}
+		return nil
+	})
+}
+
+// containsAcceptedFile checks if the sub dir contains a non-empty
+// file whose change should trigger reload.
+func containsAcceptedFile(opts WatchOptions, dirPath, subDirPath string) bool {
+	found := false
+	fp.Walk(subDirPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil || found {
+			return nil
+		}
+
+		if !info.IsDir() && info.Size() > 0 && opts.accept(dirPath, path) {
+			found = true
+			return fp.SkipDir
+		}
+		return nil
+	})
+
+	return found
+}
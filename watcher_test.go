@@ -0,0 +1,118 @@
+package qamel
+
+import (
+	"io/ioutil"
+	"os"
+	fp "path/filepath"
+	"testing"
+)
+
+func TestWatchOptionsAccept(t *testing.T) {
+	dirPath := fp.FromSlash("/project/res")
+
+	tests := []struct {
+		name     string
+		opts     WatchOptions
+		file     string
+		expected bool
+	}{{
+		name:     "all files by default",
+		file:     "main.qml",
+		expected: true,
+	}, {
+		name:     "compiled qml is ignored",
+		file:     "main.qmlc",
+		expected: false,
+	}, {
+		name:     "temporary compiled js is ignored",
+		file:     "script.jsc.tmp",
+		expected: false,
+	}, {
+		name:     "include by name",
+		opts:     WatchOptions{Include: []string{"*.qml"}},
+		file:     "view/main.qml",
+		expected: true,
+	}, {
+		name:     "not included",
+		opts:     WatchOptions{Include: []string{"*.qml"}},
+		file:     "img/logo.png",
+		expected: false,
+	}, {
+		name:     "include by relative path",
+		opts:     WatchOptions{Include: []string{"view/*.js"}},
+		file:     "view/helper.js",
+		expected: true,
+	}, {
+		name:     "exclude wins over include",
+		opts:     WatchOptions{Include: []string{"*.qml"}, Exclude: []string{"*Test.qml"}},
+		file:     "MainTest.qml",
+		expected: false,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			filePath := fp.Join(dirPath, fp.FromSlash(test.file))
+			if result := test.opts.accept(dirPath, filePath); result != test.expected {
+				t.Errorf("expected %v, got %v", test.expected, result)
+			}
+		})
+	}
+}
+
+func TestWatchOptionsValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    WatchOptions
+		wantErr bool
+	}{
+		{name: "empty", opts: WatchOptions{}},
+		{name: "valid patterns", opts: WatchOptions{Include: []string{"*.qml"}, Exclude: []string{"tmp/*"}}},
+		{name: "malformed include", opts: WatchOptions{Include: []string{"[*.qml"}}, wantErr: true},
+		{name: "malformed exclude", opts: WatchOptions{Exclude: []string{"\\"}}, wantErr: true},
+		{name: "negative debounce", opts: WatchOptions{Debounce: -1}, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.opts.validate()
+			if (err != nil) != test.wantErr {
+				t.Errorf("expected error %v, got %v", test.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestContainsAcceptedFile(t *testing.T) {
+	dirPath, err := ioutil.TempDir("", "qamel-watcher")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dirPath)
+
+	subDir := fp.Join(dirPath, "view", "nested")
+	if err = os.MkdirAll(subDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := WatchOptions{Include: []string{"*.qml"}}
+	if containsAcceptedFile(opts, dirPath, fp.Join(dirPath, "view")) {
+		t.Error("empty dir should not contain accepted file")
+	}
+
+	writeFile := func(name, content string) {
+		if err := ioutil.WriteFile(fp.Join(subDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writeFile("logo.png", "png")
+	writeFile("Empty.qml", "")
+	if containsAcceptedFile(opts, dirPath, fp.Join(dirPath, "view")) {
+		t.Error("dir with excluded and empty files should not contain accepted file")
+	}
+
+	writeFile("Main.qml", "import QtQuick 2.12")
+	if !containsAcceptedFile(opts, dirPath, fp.Join(dirPath, "view")) {
+		t.Error("dir with nested qml file should contain accepted file")
+	}
+}
@@ -0,0 +1,197 @@
+package qamel
+
+// #include <stdint.h>
+// #include <stdlib.h>
+// #include <stdbool.h>
+// #include "window.h"
+import "C"
+import (
+	"sync"
+	"unsafe"
+)
+
+// windowCallbacks is the list of Go callbacks that registered to a window.
+type windowCallbacks struct {
+	closing            func() bool
+	resize             func(width, height int)
+	move               func(x, y int)
+	windowStateChanged func(state WindowStates)
+	activeChanged      func(active bool)
+	visibilityChanged  func(visibility Visibility)
+}
+
+var (
+	windowCallbacksMutex = sync.RWMutex{}
+	mapWindowCallbacks   = map[unsafe.Pointer]*windowCallbacks{}
+)
+
+// setWindowCallback modifies the callbacks of the window with the specified pointer and
+// handle ID. The callbacks will be removed once the window destroyed.
+func setWindowCallback(ptr unsafe.Pointer, id uint64, setter func(*windowCallbacks)) {
+	windowCallbacksMutex.Lock()
+	defer windowCallbacksMutex.Unlock()
+
+	callbacks, ok := mapWindowCallbacks[ptr]
+	if !ok {
+		// The cleanup can't be added once the window destroyed,
+		// so the callbacks are not saved to avoid leaking them.
+		cleanup := func() {
+			windowCallbacksMutex.Lock()
+			delete(mapWindowCallbacks, ptr)
+			windowCallbacksMutex.Unlock()
+		}
+
+		if _, ok := addHandleCleanup(ptr, id, cleanup); !ok {
+			return
+		}
+
+		callbacks = &windowCallbacks{}
+		mapWindowCallbacks[ptr] = callbacks
+	}
+
+	setter(callbacks)
+}
+
+// getWindowCallbacks returns copy of the callbacks of the window with the specified pointer.
+func getWindowCallbacks(ptr unsafe.Pointer) windowCallbacks {
+	windowCallbacksMutex.RLock()
+	defer windowCallbacksMutex.RUnlock()
+
+	if callbacks, ok := mapWindowCallbacks[ptr]; ok {
+		return *callbacks
+	}
+
+	return windowCallbacks{}
+}
+
+// OnClosing registers callback that will be called when user tries to close the window.
+// If the callback returns false, the window will not be closed. The callback is called
+// in the GUI thread. Pass nil to remove the callback.
+func (view Viewer) OnClosing(callback func() bool) {
+	setWindowCallback(view.ptr, view.id, func(c *windowCallbacks) { c.closing = callback })
+}
+
+// OnResize registers callback that will be called when the size of the window changed.
+// The callback is called in the GUI thread. Pass nil to remove the callback.
+func (view Viewer) OnResize(callback func(width, height int)) {
+	setWindowCallback(view.ptr, view.id, func(c *windowCallbacks) { c.resize = callback })
+}
+
+// OnMove registers callback that will be called when the position of the window changed.
+// The callback is called in the GUI thread. Pass nil to remove the callback.
+func (view Viewer) OnMove(callback func(x, y int)) {
+	setWindowCallback(view.ptr, view.id, func(c *windowCallbacks) { c.move = callback })
+}
+
+// OnWindowStateChanged registers callback that will be called when the window is minimized,
+// maximized, made fullscreen or restored to normal state. The callback is called in the
+// GUI thread. Pass nil to remove the callback.
+func (view Viewer) OnWindowStateChanged(callback func(state WindowStates)) {
+	setWindowCallback(view.ptr, view.id, func(c *windowCallbacks) { c.windowStateChanged = callback })
+}
+
+// OnActiveChanged registers callback that will be called when the window gains or loses
+// the keyboard focus. The callback is called in the GUI thread. Pass nil to remove the callback.
+func (view Viewer) OnActiveChanged(callback func(active bool)) {
+	setWindowCallback(view.ptr, view.id, func(c *windowCallbacks) { c.activeChanged = callback })
+}
+
+// OnVisibilityChanged registers callback that will be called when the visibility of the
+// window changed. The callback is called in the GUI thread. Pass nil to remove the callback.
+func (view Viewer) OnVisibilityChanged(callback func(visibility Visibility)) {
+	setWindowCallback(view.ptr, view.id, func(c *windowCallbacks) { c.visibilityChanged = callback })
+}
+
+// OnClosing registers callback that will be called when user tries to close the window.
+// If the callback returns false, the window will not be closed. The callback is called
+// in the GUI thread. Pass nil to remove the callback.
+func (w QuickWindow) OnClosing(callback func() bool) {
+	setWindowCallback(w.ptr, w.id, func(c *windowCallbacks) { c.closing = callback })
+}
+
+// OnResize registers callback that will be called when the size of the window changed.
+// The callback is called in the GUI thread. Pass nil to remove the callback.
+func (w QuickWindow) OnResize(callback func(width, height int)) {
+	setWindowCallback(w.ptr, w.id, func(c *windowCallbacks) { c.resize = callback })
+}
+
+// OnMove registers callback that will be called when the position of the window changed.
+// The callback is called in the GUI thread. Pass nil to remove the callback.
+func (w QuickWindow) OnMove(callback func(x, y int)) {
+	setWindowCallback(w.ptr, w.id, func(c *windowCallbacks) { c.move = callback })
+}
+
+// OnWindowStateChanged registers callback that will be called when the window is minimized,
+// maximized, made fullscreen or restored to normal state. The callback is called in the
+// GUI thread. Pass nil to remove the callback.
+func (w QuickWindow) OnWindowStateChanged(callback func(state WindowStates)) {
+	setWindowCallback(w.ptr, w.id, func(c *windowCallbacks) { c.windowStateChanged = callback })
+}
+
+// OnActiveChanged registers callback that will be called when the window gains or loses
+// the keyboard focus. The callback is called in the GUI thread. Pass nil to remove the callback.
+func (w QuickWindow) OnActiveChanged(callback func(active bool)) {
+	setWindowCallback(w.ptr, w.id, func(c *windowCallbacks) { c.activeChanged = callback })
+}
+
+// OnVisibilityChanged registers callback that will be called when the visibility of the
+// window changed. The callback is called in the GUI thread. Pass nil to remove the callback.
+func (w QuickWindow) OnVisibilityChanged(callback func(visibility Visibility)) {
+	setWindowCallback(w.ptr, w.id, func(c *windowCallbacks) { c.visibilityChanged = callback })
+}
+
+//export qamelWindowClosing
+func qamelWindowClosing(ptr unsafe.Pointer) C.bool {
+	callback := getWindowCallbacks(ptr).closing
+	if callback == nil {
+		return C.bool(true)
+	}
+
+	return C.bool(callback())
+}
+
+//export qamelWindowResized
+func qamelWindowResized(ptr unsafe.Pointer, width, height C.int) {
+	if callback := getWindowCallbacks(ptr).resize; callback != nil {
+		callback(int(width), int(height))
+	}
+}
+
+//export qamelWindowMoved
+func qamelWindowMoved(ptr unsafe.Pointer, x, y C.int) {
+	if callback := getWindowCallbacks(ptr).move; callback != nil {
+		callback(int(x), int(y))
+	}
+}
+
+//export qamelWindowStateChanged
+func qamelWindowStateChanged(ptr unsafe.Pointer, state C.int) {
+	if callback := getWindowCallbacks(ptr).windowStateChanged; callback != nil {
+		callback(WindowStates(state))
+	}
+}
+
+//export qamelWindowActiveChanged
+func qamelWindowActiveChanged(ptr unsafe.Pointer, active C.bool) {
+	if callback := getWindowCallbacks(ptr).activeChanged; callback != nil {
+		callback(bool(active))
+	}
+}
+
+//export qamelWindowVisibilityChanged
+func qamelWindowVisibilityChanged(ptr unsafe.Pointer, visibility C.int) {
+	if callback := getWindowCallbacks(ptr).visibilityChanged; callback != nil {
+		callback(Visibility(visibility))
+	}
+}
+
+//export qamelWindowDestroyed
+func qamelWindowDestroyed(ptr unsafe.Pointer) {
+	handleMutex.Lock()
+	handle, ok := mapHandle[ptr]
+	handleMutex.Unlock()
+
+	if ok {
+		releaseHandle(ptr, handle.id)
+	}
+}
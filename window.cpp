@@ -0,0 +1,309 @@
+#include "_cgo_export.h"
+#include "window.h"
+#include "utils.h"
+#include "handle.h"
+#include "hotreload.h"
+#include <QGuiApplication>
+#include <QQuickWindow>
+#include <QQmlEngine>
+#include <QQuickView>
+#include <QWindow>
+#include <QEvent>
+#include <QList>
+#include <QMetaObject>
+#include <QVariant>
+#include <QRect>
+#include <QElapsedTimer>
+#include <functional>
+
+// QamelWindowEventFilter asks Go whether the window may be closed.
+class QamelWindowEventFilter : public QObject {
+public:
+    QamelWindowEventFilter(QObject *parent) : QObject(parent) {}
+
+    bool eventFilter(QObject *obj, QEvent *e) override {
+        if (e->type() == QEvent::Close && !qamelWindowClosing(obj)) {
+            e->ignore();
+            return true;
+        }
+
+        return false;
+    }
+};
+
+// QamelFrameTracker reports the timing of each frame of the window to Go. The signals
+// are emitted in the render thread, so the slots are connected directly and the
+// timing is only accessed from there.
+class QamelFrameTracker : public QObject {
+public:
+    QamelFrameTracker(QQuickWindow *window) : QObject(window) {
+        _timer.start();
+
+        connect(window, &QQuickWindow::beforeSynchronizing, this, [this]() {
+            _syncStart = _timer.nsecsElapsed();
+        }, Qt::DirectConnection);
+
+        connect(window, &QQuickWindow::beforeRendering, this, [this]() {
+            _renderStart = _timer.nsecsElapsed();
+        }, Qt::DirectConnection);
+
+        connect(window, &QQuickWindow::afterRendering, this, [this]() {
+            _renderTime = _timer.nsecsElapsed() - _renderStart;
+        }, Qt::DirectConnection);
+
+        connect(window, &QQuickWindow::frameSwapped, this, [this, window]() {
+            qamelWindowFrameSwapped(window, _syncStart, _timer.nsecsElapsed(), _renderTime);
+        }, Qt::DirectConnection);
+    }
+
+private:
+    QElapsedTimer _timer;
+    qint64 _syncStart = 0;
+    qint64 _renderStart = 0;
+    qint64 _renderTime = 0;
+};
+
+// queueOnce queues func to be called in the event loop of window, unless there is
+// already a queued call with the same flag that not called yet.
+static void queueOnce(QQuickWindow *window, const char *flag, std::function<void()> func) {
+    if (window->property(flag).toBool()) {
+        return;
+    }
+
+    window->setProperty(flag, true);
+    QMetaObject::invokeMethod(window, [window, flag, func]() {
+        window->setProperty(flag, false);
+        func();
+    }, Qt::QueuedConnection);
+}
+
+void qamelTrackWindow(QQuickWindow *window) {
+    if (window == nullptr || window->property("_qamelTracked").toBool()) {
+        return;
+    }
+
+    window->setProperty("_qamelTracked", true);
+    qamelTrackNormalGeometry(window);
+    window->installEventFilter(new QamelWindowEventFilter(window));
+
+    // Width and height (also x and y) are changed separately, so the callback
+    // is queued to report them once after both of them are updated.
+    auto resized = [window]() {
+        queueOnce(window, "_qamelResizeQueued", [window]() {
+            qamelWindowResized(window, window->width(), window->height());
+        });
+    };
+    auto moved = [window]() {
+        queueOnce(window, "_qamelMoveQueued", [window]() {
+            qamelWindowMoved(window, window->x(), window->y());
+        });
+    };
+
+    QObject::connect(window, &QWindow::widthChanged, window, resized);
+    QObject::connect(window, &QWindow::heightChanged, window, resized);
+    QObject::connect(window, &QWindow::xChanged, window, moved);
+    QObject::connect(window, &QWindow::yChanged, window, moved);
+    QObject::connect(window, &QWindow::windowStateChanged, window, [window]() {
+        qamelWindowStateChanged(window, int(window->windowStates()));
+    });
+    QObject::connect(window, &QWindow::activeChanged, window, [window]() {
+        qamelWindowActiveChanged(window, window->isActive());
+    });
+    QObject::connect(window, &QWindow::visibilityChanged, window, [window](QWindow::Visibility visibility) {
+        qamelWindowVisibilityChanged(window, int(visibility));
+    });
+    QObject::connect(window, &QObject::destroyed, [window]() {
+        qamelWindowDestroyed(window);
+    });
+}
+
+QamelWindowHandle* qamelWindowHandles(const QList<QQuickWindow*> &windows, int* count) {
+    *count = windows.size();
+    if (windows.isEmpty()) {
+        return nullptr;
+    }
+
+    QamelWindowHandle* result = static_cast<QamelWindowHandle*>(malloc(windows.size() * sizeof(QamelWindowHandle)));
+    for (int i = 0; i < windows.size(); i++) {
+        qamelTrackWindow(windows.at(i));
+        result[i].ptr = windows.at(i);
+        result[i].id = qamelWindowHandle(windows.at(i));
+    }
+
+    return result;
+}
+
+QQmlEngine* qamelWindowEngine(QWindow *window) {
+    if (QQuickView *view = qobject_cast<QQuickView*>(window)) {
+        return view->engine();
+    }
+    return window != nullptr ? qmlEngine(window) : nullptr;
+}
+
+QamelWindowHandle* Window_TopLevelWindows(int* count) {
+    QamelWindowHandle* result = nullptr;
+    *count = 0;
+
+    qamelRunOnGuiThread(qApp, [&]() {
+        QList<QQuickWindow*> windows;
+        for (QWindow *window : QGuiApplication::topLevelWindows()) {
+            if (QQuickWindow *quickWindow = qobject_cast<QQuickWindow*>(window)) {
+                windows.append(quickWindow);
+            }
+        }
+
+        result = qamelWindowHandles(windows, count);
+    });
+
+    return result;
+}
+
+QamelWindowHandle Window_FocusWindow() {
+    QamelWindowHandle result = {};
+    qamelRunOnGuiThread(qApp, [&]() {
+        QQuickWindow *window = qobject_cast<QQuickWindow*>(QGuiApplication::focusWindow());
+        if (window != nullptr) {
+            qamelTrackWindow(window);
+            result.ptr = window;
+            result.id = qamelWindowHandle(window);
+        }
+    });
+
+    return result;
+}
+
+bool Window_Show(void* ptr, uint64_t id) {
+    return qamelRunOnHandle<QQuickWindow>(ptr, id, [&](QQuickWindow *window) {
+        window->show();
+    });
+}
+
+bool Window_Close(void* ptr, uint64_t id) {
+    return qamelRunOnHandle<QQuickWindow>(ptr, id, [&](QQuickWindow *window) {
+        window->close();
+    });
+}
+
+bool Window_SetTitle(void* ptr, uint64_t id, char* title) {
+    QString windowTitle(title);
+    return qamelRunOnHandle<QQuickWindow>(ptr, id, [&](QQuickWindow *window) {
+        window->setTitle(windowTitle);
+    });
+}
+
+bool Window_SetWidth(void* ptr, uint64_t id, int width) {
+    return qamelRunOnHandle<QQuickWindow>(ptr, id, [&](QQuickWindow *window) {
+        window->setWidth(width);
+    });
+}
+
+bool Window_SetHeight(void* ptr, uint64_t id, int height) {
+    return qamelRunOnHandle<QQuickWindow>(ptr, id, [&](QQuickWindow *window) {
+        window->setHeight(height);
+    });
+}
+
+bool Window_SetPosition(void* ptr, uint64_t id, int x, int y) {
+    return qamelRunOnHandle<QQuickWindow>(ptr, id, [&](QQuickWindow *window) {
+        window->setPosition(x, y);
+    });
+}
+
+bool Window_SetWindowStates(void* ptr, uint64_t id, int state) {
+    return qamelRunOnHandle<QQuickWindow>(ptr, id, [&](QQuickWindow *window) {
+        window->setWindowStates(Qt::WindowStates(state));
+    });
+}
+
+int Window_Width(void* ptr, uint64_t id) {
+    int width = 0;
+    qamelRunOnHandle<QQuickWindow>(ptr, id, [&](QQuickWindow *window) { width = window->width(); });
+    return width;
+}
+
+int Window_Height(void* ptr, uint64_t id) {
+    int height = 0;
+    qamelRunOnHandle<QQuickWindow>(ptr, id, [&](QQuickWindow *window) { height = window->height(); });
+    return height;
+}
+
+void Window_Position(void* ptr, uint64_t id, int* x, int* y) {
+    *x = 0;
+    *y = 0;
+    qamelRunOnHandle<QQuickWindow>(ptr, id, [&](QQuickWindow *window) {
+        *x = window->x();
+        *y = window->y();
+    });
+}
+
+int Window_WindowStates(void* ptr, uint64_t id) {
+    int states = 0;
+    qamelRunOnHandle<QQuickWindow>(ptr, id, [&](QQuickWindow *window) { states = int(window->windowStates()); });
+    return states;
+}
+
+bool Window_NormalGeometry(void* ptr, uint64_t id, int* x, int* y, int* width, int* height, int* states, QamelScreen* screen) {
+    *x = 0;
+    *y = 0;
+    *width = 0;
+    *height = 0;
+    *states = 0;
+    *screen = {};
+
+    return qamelRunOnHandle<QQuickWindow>(ptr, id, [&](QQuickWindow *window) {
+        QRect geometry = qamelNormalGeometry(window);
+        *x = geometry.x();
+        *y = geometry.y();
+        *width = geometry.width();
+        *height = geometry.height();
+        *states = int(window->windowStates());
+        *screen = qamelScreenInfo(window->screen());
+    });
+}
+
+QamelScreen Window_Screen(void* ptr, uint64_t id) {
+    QamelScreen screen = {};
+    qamelRunOnHandle<QQuickWindow>(ptr, id, [&](QQuickWindow *window) { screen = qamelScreenInfo(window->screen()); });
+    return screen;
+}
+
+QamelImage Window_Grab(void* ptr, uint64_t id, bool* live) {
+    QamelImage image = qamelImage(QImage());
+    *live = qamelRunOnHandle<QQuickWindow>(ptr, id, [&](QQuickWindow *window) {
+        image = qamelImage(window->grabWindow());
+    });
+    return image;
+}
+
+bool Window_Reload(void* ptr, uint64_t id, bool* live) {
+    // The reload is done by the Viewer or Engine that owns the window. For QQuickView,
+    // qmlEngine() returns null since the root object is the content item, not the window.
+    QObject *owner = nullptr;
+    *live = qamelRunOnHandle<QQuickWindow>(ptr, id, [&](QQuickWindow *window) {
+        if (QQuickView *view = qobject_cast<QQuickView*>(window)) {
+            owner = view;
+        } else {
+            owner = qmlEngine(window);
+        }
+
+        // The reload is queued, so the owner may delete the window after this returns
+        if (owner != nullptr && owner->metaObject()->indexOfMethod("reload()") >= 0) {
+            QMetaObject::invokeMethod(owner, "reload", Qt::QueuedConnection);
+        } else {
+            owner = nullptr;
+        }
+    });
+
+    return owner != nullptr;
+}
+
+bool Window_TrackFrames(void* ptr, uint64_t id) {
+    return qamelRunOnHandle<QQuickWindow>(ptr, id, [&](QQuickWindow *window) {
+        if (window->property("_qamelFramesTracked").toBool()) {
+            return;
+        }
+
+        window->setProperty("_qamelFramesTracked", true);
+        new QamelFrameTracker(window);
+    });
+}
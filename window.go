@@ -0,0 +1,332 @@
+package qamel
+
+// #include <stdint.h>
+// #include <stdlib.h>
+// #include <stdbool.h>
+// #include "window.h"
+import "C"
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"time"
+	"unsafe"
+)
+
+// Window is the top level QML window, either the Viewer or the window
+// that created by Engine from an ApplicationWindow or Window QML item.
+type Window interface {
+	// Show shows the window.
+	Show() error
+
+	// Close closes the window. For Viewer, the view is destroyed as well.
+	Close() error
+
+	// SetTitle sets the title of the window.
+	SetTitle(title string) error
+
+	// SetWidth sets the width of the window.
+	SetWidth(width int) error
+
+	// SetHeight sets the height of the window.
+	SetHeight(height int) error
+
+	// SetPosition sets the position of the window on the desktop.
+	SetPosition(x int, y int) error
+
+	// SetWindowStates sets the screen-occupation state of the window.
+	SetWindowStates(state WindowStates) error
+
+	// Width returns the width of the window, excluding any window frame.
+	Width() int
+
+	// Height returns the height of the window, excluding any window frame.
+	Height() int
+
+	// Position returns the position of the window on the desktop, excluding any window frame.
+	Position() (x int, y int)
+
+	// WindowStates returns the current screen-occupation state of the window.
+	WindowStates() WindowStates
+
+	// Screen returns the screen on which the window is displayed.
+	Screen() Screen
+
+	// SaveGeometry saves the current geometry and state of the window into bytes.
+	SaveGeometry() []byte
+
+	// RestoreGeometry restores the geometry and state of the window that saved by SaveGeometry.
+	RestoreGeometry(data []byte) error
+
+	// Grab renders the content of the window and returns it as image.
+	Grab() (image.Image, error)
+
+	// Reload reloads the QML source of the window, i.e. its Viewer or Engine.
+	Reload() error
+
+	// FrameStats returns the rendering performance of the window.
+	FrameStats() FrameStats
+
+	// OnFrameStats registers callback that will be called with the frame stats every interval.
+	OnFrameStats(interval time.Duration, callback func(stats FrameStats))
+
+	// OnClosing registers callback that will be called when user tries to close the window.
+	OnClosing(callback func() bool)
+
+	// OnResize registers callback that will be called when the size of the window changed.
+	OnResize(callback func(width, height int))
+
+	// OnMove registers callback that will be called when the position of the window changed.
+	OnMove(callback func(x, y int))
+
+	// OnWindowStateChanged registers callback that will be called when the window state changed.
+	OnWindowStateChanged(callback func(state WindowStates))
+
+	// OnActiveChanged registers callback that will be called when the window gains or loses focus.
+	OnActiveChanged(callback func(active bool))
+
+	// OnVisibilityChanged registers callback that will be called when the visibility changed.
+	OnVisibilityChanged(callback func(visibility Visibility))
+}
+
+var (
+	_ Window = Viewer{}
+	_ Window = QuickWindow{}
+)
+
+// QuickWindow is the QML window which wraps QQuickWindow. It's used for
+// windows that not created by Viewer, e.g. the windows created by Engine.
+type QuickWindow struct {
+	ptr unsafe.Pointer
+	id  uint64
+}
+
+// valid checks if the native window is still live.
+func (w QuickWindow) valid() bool {
+	return handleValid(w.ptr, w.id)
+}
+
+// Show shows the window.
+func (w QuickWindow) Show() error {
+	return nativeError(w.ptr, C.Window_Show(w.ptr, C.uint64_t(w.id)))
+}
+
+// Close closes the window. Unlike Viewer, the window is only hidden so it could be shown
+// again, unless the window is the last one and the application quits once it's closed.
+func (w QuickWindow) Close() error {
+	return nativeError(w.ptr, C.Window_Close(w.ptr, C.uint64_t(w.id)))
+}
+
+// SetTitle sets the title of the window. In the windowing system, the title
+// usually appears in the window's title bar and task bar.
+func (w QuickWindow) SetTitle(title string) error {
+	cTitle := C.CString(title)
+	defer C.free(unsafe.Pointer(cTitle))
+	return nativeError(w.ptr, C.Window_SetTitle(w.ptr, C.uint64_t(w.id), cTitle))
+}
+
+// SetWidth sets the width of the window.
+func (w QuickWindow) SetWidth(width int) error {
+	return nativeError(w.ptr, C.Window_SetWidth(w.ptr, C.uint64_t(w.id), C.int(int32(width))))
+}
+
+// SetHeight sets the height of the window.
+func (w QuickWindow) SetHeight(height int) error {
+	return nativeError(w.ptr, C.Window_SetHeight(w.ptr, C.uint64_t(w.id), C.int(int32(height))))
+}
+
+// SetPosition sets the position of the window on the desktop to x, y.
+func (w QuickWindow) SetPosition(x int, y int) error {
+	return nativeError(w.ptr, C.Window_SetPosition(w.ptr, C.uint64_t(w.id), C.int(int32(x)), C.int(int32(y))))
+}
+
+// SetWindowStates sets the screen-occupation state of the window.
+func (w QuickWindow) SetWindowStates(state WindowStates) error {
+	return nativeError(w.ptr, C.Window_SetWindowStates(w.ptr, C.uint64_t(w.id), C.int(state)))
+}
+
+// Width returns the width of the window, excluding any window frame.
+func (w QuickWindow) Width() int {
+	return int(C.Window_Width(w.ptr, C.uint64_t(w.id)))
+}
+
+// Height returns the height of the window, excluding any window frame.
+func (w QuickWindow) Height() int {
+	return int(C.Window_Height(w.ptr, C.uint64_t(w.id)))
+}
+
+// Position returns the position of the window on the desktop, excluding any window frame.
+func (w QuickWindow) Position() (x int, y int) {
+	var cX, cY C.int
+	C.Window_Position(w.ptr, C.uint64_t(w.id), &cX, &cY)
+	return int(cX), int(cY)
+}
+
+// WindowStates returns the current screen-occupation state of the window.
+func (w QuickWindow) WindowStates() WindowStates {
+	return WindowStates(C.Window_WindowStates(w.ptr, C.uint64_t(w.id)))
+}
+
+// Screen returns the screen on which the window is displayed.
+func (w QuickWindow) Screen() Screen {
+	return goScreen(C.Window_Screen(w.ptr, C.uint64_t(w.id)))
+}
+
+// SaveGeometry saves the current geometry and state of the window into bytes, which can be
+// stored and used later by RestoreGeometry to restore the window placement. For maximized
+// or full screen window, its normal geometry is saved instead.
+func (w QuickWindow) SaveGeometry() []byte {
+	return saveGeometry(w.ptr, w.id)
+}
+
+// RestoreGeometry restores the geometry and state of the window that saved by SaveGeometry.
+// The window will be moved and resized if necessary, so it fits inside the available screens.
+// Minimized state is never restored.
+func (w QuickWindow) RestoreGeometry(data []byte) error {
+	return restoreGeometry(w, data)
+}
+
+// Grab renders the content of the window and returns it as image.
+func (w QuickWindow) Grab() (image.Image, error) {
+	var live C.bool
+	cImg := C.Window_Grab(w.ptr, C.uint64_t(w.id), &live)
+	if err := nativeError(w.ptr, live); err != nil {
+		return nil, err
+	}
+
+	img := goImage(cImg)
+	if img == nil {
+		return nil, fmt.Errorf("failed to grab window")
+	}
+
+	return img, nil
+}
+
+// Reload reloads the Engine that created the window. All windows of the engine will
+// be recreated, so the window becomes invalid and must be fetched again afterward.
+// The reload is queued and done in the GUI thread, so it's safe to call from any goroutine.
+func (w QuickWindow) Reload() error {
+	var live C.bool
+	ok := C.Window_Reload(w.ptr, C.uint64_t(w.id), &live)
+	if err := nativeError(w.ptr, live); err != nil {
+		return err
+	}
+
+	if !bool(ok) {
+		return fmt.Errorf("window is not created by Engine")
+	}
+
+	return nil
+}
+
+// saveGeometry saves the geometry and state of the native window in ptr as JSON. For maximized
+// or full screen window, its normal geometry is saved so it's restored properly. If it's
+// not known, only the state is saved. It returns nil if the window is not live anymore.
+func saveGeometry(ptr unsafe.Pointer, id uint64) []byte {
+	var x, y, width, height, states C.int
+	var screen C.QamelScreen
+	if !C.Window_NormalGeometry(ptr, C.uint64_t(id), &x, &y, &width, &height, &states, &screen) {
+		return nil
+	}
+
+	geometry := windowGeometry{
+		States: WindowStates(states),
+		Screen: goScreen(screen).Name,
+	}
+
+	if width > 0 && height > 0 {
+		geometry.X = int(x)
+		geometry.Y = int(y)
+		geometry.Width = int(width)
+		geometry.Height = int(height)
+	}
+
+	bt, _ := json.Marshal(&geometry)
+	return bt
+}
+
+// restoreGeometry restores the geometry and state of the window from JSON that saved by saveGeometry.
+func restoreGeometry(w Window, data []byte) error {
+	var geometry windowGeometry
+	if err := json.Unmarshal(data, &geometry); err != nil {
+		return fmt.Errorf("invalid geometry: %v", err)
+	}
+
+	// The size is only missing for maximized or full screen window
+	// whose normal geometry is unknown, so only its state is restored.
+	fillsScreen := geometry.States&(WindowMaximized|WindowFullScreen) != 0
+	hasSize := geometry.Width > 0 && geometry.Height > 0
+	if !hasSize && !fillsScreen {
+		return fmt.Errorf("invalid geometry: size must be positive")
+	}
+
+	// Restore the normal geometry first, so it's used once the window is restored
+	if hasSize {
+		rect := Rect{
+			X:      geometry.X,
+			Y:      geometry.Y,
+			Width:  geometry.Width,
+			Height: geometry.Height,
+		}
+		rect = clampToScreens(rect, geometry.Screen, screens())
+
+		if err := w.SetPosition(rect.X, rect.Y); err != nil {
+			return err
+		}
+
+		if err := w.SetWidth(rect.Width); err != nil {
+			return err
+		}
+
+		if err := w.SetHeight(rect.Height); err != nil {
+			return err
+		}
+	}
+
+	return w.SetWindowStates(geometry.States &^ WindowMinimized)
+}
+
+// goWindow returns the Window for the native QQuickWindow in the window handle.
+func goWindow(handle C.QamelWindowHandle) Window {
+	if handle.ptr == nil {
+		return nil
+	}
+
+	ptr, id := handle.ptr, uint64(handle.id)
+	if lookupHandleKind(ptr, id, windowHandle) == viewerHandle {
+		return Viewer{ptr: ptr, id: id}
+	}
+
+	return QuickWindow{ptr: ptr, id: id}
+}
+
+// goWindows converts the C array of window handles into Window
+// slice. The array is freed afterward.
+func goWindows(cWindows *C.QamelWindowHandle, count C.int) []Window {
+	if cWindows == nil {
+		return nil
+	}
+	defer C.free(unsafe.Pointer(cWindows))
+
+	handles := (*[1 << 28]C.QamelWindowHandle)(unsafe.Pointer(cWindows))[:int(count):int(count)]
+	windows := make([]Window, 0, len(handles))
+	for _, handle := range handles {
+		windows = append(windows, goWindow(handle))
+	}
+
+	return windows
+}
+
+// Windows returns all top level QML windows of the application, both
+// the Viewer and the windows that created by Engine.
+func (app Application) Windows() []Window {
+	var count C.int
+	cWindows := C.Window_TopLevelWindows(&count)
+	return goWindows(cWindows, count)
+}
+
+// FocusWindow returns the QML window that receives keyboard input,
+// or nil if there are no focused window.
+func (app Application) FocusWindow() Window {
+	return goWindow(C.Window_FocusWindow())
+}
@@ -0,0 +1,65 @@
+#pragma once
+
+#ifndef QAMEL_WINDOW_H
+#define QAMEL_WINDOW_H
+
+#include <stdint.h>
+#include <stdbool.h>
+#include "screen.h"
+#include "image.h"
+
+// QamelWindowHandle is the native window with its handle ID.
+typedef struct {
+    void* ptr;
+    uint64_t id;
+} QamelWindowHandle;
+
+#ifdef __cplusplus
+
+#include <QQuickWindow>
+#include <QList>
+
+class QQmlEngine;
+
+// qamelTrackWindow forwards the events of window to Go, i.e. the window
+// callbacks and its destruction. It's safe to call it more than once.
+void qamelTrackWindow(QQuickWindow *window);
+
+// qamelWindowHandles tracks windows and converts them into C array of window handles. It must
+// be called in the GUI thread, so the windows can't be destroyed before their handles registered.
+QamelWindowHandle* qamelWindowHandles(const QList<QQuickWindow*> &windows, int* count);
+
+// qamelWindowEngine returns the QML engine that created window, or null if there is none.
+// Unlike qmlEngine(), it also works for QQuickView whose root object is its content item.
+QQmlEngine* qamelWindowEngine(QWindow *window);
+
+extern "C" {
+#endif
+
+// Static functions
+QamelWindowHandle* Window_TopLevelWindows(int* count);
+QamelWindowHandle Window_FocusWindow();
+
+// Methods
+bool Window_Show(void* ptr, uint64_t id);
+bool Window_Close(void* ptr, uint64_t id);
+bool Window_SetTitle(void* ptr, uint64_t id, char* title);
+bool Window_SetWidth(void* ptr, uint64_t id, int width);
+bool Window_SetHeight(void* ptr, uint64_t id, int height);
+bool Window_SetPosition(void* ptr, uint64_t id, int x, int y);
+bool Window_SetWindowStates(void* ptr, uint64_t id, int state);
+int Window_Width(void* ptr, uint64_t id);
+int Window_Height(void* ptr, uint64_t id);
+void Window_Position(void* ptr, uint64_t id, int* x, int* y);
+int Window_WindowStates(void* ptr, uint64_t id);
+bool Window_NormalGeometry(void* ptr, uint64_t id, int* x, int* y, int* width, int* height, int* states, QamelScreen* screen);
+QamelScreen Window_Screen(void* ptr, uint64_t id);
+QamelImage Window_Grab(void* ptr, uint64_t id, bool* live);
+bool Window_Reload(void* ptr, uint64_t id, bool* live);
+bool Window_TrackFrames(void* ptr, uint64_t id);
+
+#ifdef __cplusplus
+}
+#endif
+
+#endif